@@ -37,9 +37,10 @@ func (ctrl Controller) Register(c *gin.Context) {
 			c.JSON(int(statusCode), response)
 		} else {
 			responseUser := ResponseUser{
-				Name:  newUser.Name,
-				Email: newUser.Email,
-				Token: token,
+				Name:     newUser.Name,
+				Email:    newUser.Email,
+				TimeZone: newUser.TimeZone,
+				Token:    token,
 			}
 			response := Response{
 				Status: "success",
@@ -75,9 +76,10 @@ func (ctrl Controller) Login(c *gin.Context) {
 			c.JSON(int(statusCode), response)
 		} else {
 			responseUser := ResponseUser{
-				Name:  user.Name,
-				Email: user.Email,
-				Token: token,
+				Name:     user.Name,
+				Email:    user.Email,
+				TimeZone: user.TimeZone,
+				Token:    token,
 			}
 			response := Response{
 				Status: "success",
@@ -112,9 +114,10 @@ func (ctrl Controller) UpdateAccount(c *gin.Context) {
 			c.JSON(int(statusCode), response)
 		} else {
 			responseUser := ResponseUser{
-				Name:  updatedUser.Name,
-				Email: updatedUser.Email,
-				Token: token,
+				Name:     updatedUser.Name,
+				Email:    updatedUser.Email,
+				TimeZone: updatedUser.TimeZone,
+				Token:    token,
 			}
 			response := Response{
 				Status: "success",
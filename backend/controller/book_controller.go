@@ -19,7 +19,7 @@ func (ctrl Controller) SearchBooks(c *gin.Context) {
 		response := Response{
 			Status: "error",
 			Error:  err.Error(),
-			Data:   []ResponseBook{},
+			Data:   entity.SearchResponse{},
 		}
 		c.JSON(int(statusCode), response)
 	} else {
@@ -31,3 +31,422 @@ func (ctrl Controller) SearchBooks(c *gin.Context) {
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// 書籍詳細取得コントローラ
+func (ctrl Controller) GetBook(c *gin.Context) {
+	var s service.Service
+	book, statusCode, err := s.GetBook(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.BookDetailResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   book,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ISBNによる書籍取得コントローラ
+func (ctrl Controller) GetBookByISBN(c *gin.Context) {
+	var s service.Service
+	book, statusCode, err := s.GetBookByISBN(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseBook{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   book,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍メタデータのキャッシュの統計情報取得コントローラ
+func (ctrl Controller) GetBookCacheStats(c *gin.Context) {
+	var s service.Service
+	stats, statusCode, err := s.GetBookCacheStats(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.BookCacheStats{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   stats,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍の表紙画像取得コントローラ
+func (ctrl Controller) GetBookCover(c *gin.Context) {
+	var s service.Service
+	image, statusCode, err := s.GetBookCover(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.CoverImage{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		c.Header("Cache-Control", image.CacheControl)
+		c.Header("ETag", image.ETag)
+		if image.NotModified {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, image.ContentType, image.Data)
+	}
+}
+
+// 書籍登録コントローラ
+func (ctrl Controller) CreateBook(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.CreateBook(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.BookDetailResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 書籍データの修正提案コントローラ
+func (ctrl Controller) SuggestBookEdit(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.SuggestBookEdit(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseBookEdit{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 書籍データの修正提案一覧取得コントローラ
+func (ctrl Controller) GetBookEdits(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.GetBookEdits(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseBookEdit{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// モデレータ用の書籍データの修正提案一覧取得コントローラ
+func (ctrl Controller) GetBookEditQueue(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.GetBookEditQueue(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseBookEdit{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍データの修正提案承認コントローラ
+func (ctrl Controller) ApproveBookEdit(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.ApproveBookEdit(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseBookEdit{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍データの修正提案却下コントローラ
+func (ctrl Controller) RejectBookEdit(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.RejectBookEdit(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseBookEdit{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍データの変更履歴取得コントローラ
+func (ctrl Controller) GetBookHistory(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.GetBookHistory(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseBookChange{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍の重複候補一覧取得コントローラ
+func (ctrl Controller) GetDuplicateCandidates(c *gin.Context) {
+	var s service.Service
+	candidates, statusCode, err := s.GetDuplicateCandidates(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseDuplicateCandidate{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   candidates,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍の重複検出コントローラ
+func (ctrl Controller) ScanDuplicateBooks(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.ScanDuplicateBooks(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   "",
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "scan started",
+		}
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// 書籍の重複候補の却下コントローラ
+func (ctrl Controller) DismissDuplicateCandidate(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.DismissDuplicateCandidate(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseDuplicateCandidate{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "dismissed successfully",
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍の統合コントローラ
+func (ctrl Controller) MergeBooks(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.MergeBooks(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.MergeBooksResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 著者詳細取得コントローラ
+func (ctrl Controller) GetAuthor(c *gin.Context) {
+	var s service.Service
+	author, statusCode, err := s.GetAuthor(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.AuthorResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   author,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 著者の別名登録コントローラ
+func (ctrl Controller) AddAuthorAlias(c *gin.Context) {
+	var s service.Service
+	alias, statusCode, err := s.AddAuthorAlias(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseAuthorAlias{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   alias,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 作品詳細取得コントローラ
+func (ctrl Controller) GetWork(c *gin.Context) {
+	var s service.Service
+	work, statusCode, err := s.GetWork(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.WorkResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   work,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ジャンル一覧取得コントローラ
+func (ctrl Controller) GetGenres(c *gin.Context) {
+	var s service.Service
+	genres, statusCode, err := s.GetGenres(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseGenre{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   genres,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
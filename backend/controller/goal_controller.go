@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 読書目標一覧取得コントローラ
+func (ctrl Controller) GetGoals(c *gin.Context) {
+	var s service.Service
+	goals, statusCode, err := s.GetGoals(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.GoalResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   goals,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 読書目標取得コントローラ
+func (ctrl Controller) GetGoal(c *gin.Context) {
+	var s service.Service
+	goal, statusCode, err := s.GetGoal(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.GoalResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   goal,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 読書目標登録コントローラ
+func (ctrl Controller) CreateGoal(c *gin.Context) {
+	var s service.Service
+	newGoal, statusCode, err := s.CreateGoal(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.GoalResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   newGoal,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 読書目標更新コントローラ
+func (ctrl Controller) UpdateGoal(c *gin.Context) {
+	var s service.Service
+	goal, statusCode, err := s.UpdateGoal(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.GoalResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   goal,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 読書目標削除コントローラ
+func (ctrl Controller) DeleteGoal(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.DeleteGoal(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.GoalResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "deleted successfully",
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseImportJob entity.ResponseImportJob
+
+// GoodreadsのCSVインポートコントローラ
+func (ctrl Controller) ImportGoodreads(c *gin.Context) {
+	var s service.Service
+	job, statusCode, err := s.ImportGoodreads(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseImportJob{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   job,
+		}
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// Kindleのハイライトインポートコントローラ
+func (ctrl Controller) ImportKindleClippings(c *gin.Context) {
+	var s service.Service
+	job, statusCode, err := s.ImportKindleClippings(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseImportJob{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   job,
+		}
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// インポートジョブ取得コントローラ
+func (ctrl Controller) GetImportJob(c *gin.Context) {
+	var s service.Service
+	job, statusCode, err := s.GetImportJob(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseImportJob{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   job,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 通知一覧取得コントローラ
+func (ctrl Controller) GetNotifications(c *gin.Context) {
+	var s service.Service
+	notifications, statusCode, err := s.GetNotifications(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.ResponseNotification{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   notifications,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 通知の既読化コントローラ
+func (ctrl Controller) ReadNotification(c *gin.Context) {
+	var s service.Service
+	notification, statusCode, err := s.ReadNotification(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseNotification{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   notification,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 書籍の価格取得コントローラ
+func (ctrl Controller) GetBookPrices(c *gin.Context) {
+	var s service.Service
+	prices, statusCode, err := s.GetBookPrices(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.BookPricesResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   prices,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 書籍の価格の監視登録コントローラ
+func (ctrl Controller) WatchBookPrice(c *gin.Context) {
+	var s service.Service
+	watch, statusCode, err := s.WatchBookPrice(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponsePriceWatch{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   watch,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 書籍の価格の監視解除コントローラ
+func (ctrl Controller) UnwatchBookPrice(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.UnwatchBookPrice(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponsePriceWatch{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "deleted successfully",
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
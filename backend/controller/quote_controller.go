@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseQuote entity.ResponseQuote
+
+// レビューに紐づく引用取得コントローラ
+func (ctrl Controller) GetQuotes(c *gin.Context) {
+	var s service.Service
+	quotes, statusCode, err := s.GetQuotes(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []ResponseQuote{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   quotes,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 引用登録コントローラ
+func (ctrl Controller) CreateQuote(c *gin.Context) {
+	var s service.Service
+	newQuote, statusCode, err := s.CreateQuote(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseQuote{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   newQuote,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// 引用更新コントローラ
+func (ctrl Controller) UpdateQuote(c *gin.Context) {
+	var s service.Service
+	updatedQuote, statusCode, err := s.UpdateQuote(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseQuote{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   updatedQuote,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 引用削除コントローラ
+func (ctrl Controller) DeleteQuote(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.DeleteQuote(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   ResponseQuote{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "deleted successfully",
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 引用検索コントローラ
+func (ctrl Controller) SearchQuotes(c *gin.Context) {
+	var s service.Service
+	quotes, statusCode, err := s.SearchQuotes(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []ResponseQuote{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   quotes,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
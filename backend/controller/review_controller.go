@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
@@ -141,3 +143,139 @@ func (ctrl Controller) FilterReviewByTag(c *gin.Context) {
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// レビューのエクスポートコントローラ
+func (ctrl Controller) ExportReviews(c *gin.Context) {
+	var s service.Service
+	export, statusCode, err := s.ExportReviews(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []ResponseReview{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		c.Header("Content-Type", export.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+		c.Status(http.StatusOK)
+		// 書き出し開始後はステータスコードを変更できないため、エラーはログに残す
+		if err := export.Stream(c.Writer); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// レビュー一括操作コントローラ
+func (ctrl Controller) BulkUpdateReviews(c *gin.Context) {
+	var s service.Service
+	result, statusCode, err := s.BulkUpdateReviews(c)
+
+	if err != nil {
+		// レビューごとの処理結果を確認できるよう、エラー時も処理結果を返す
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   result,
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   result,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 詳細な読書統計情報取得コントローラ
+func (ctrl Controller) GetDetailedReviewStats(c *gin.Context) {
+	var s service.Service
+	stats, statusCode, err := s.GetDetailedReviewStats(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.DetailedStatsResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   stats,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 読書の活動状況取得コントローラ
+func (ctrl Controller) GetReadingActivity(c *gin.Context) {
+	var s service.Service
+	activity, statusCode, err := s.GetReadingActivity(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ReadingActivityResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   activity,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 読書中の書籍一覧取得コントローラ
+func (ctrl Controller) GetCurrentlyReading(c *gin.Context) {
+	var s service.Service
+	currentlyReading, statusCode, err := s.GetCurrentlyReading(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.CurrentlyReadingResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   currentlyReading,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// 年間の読書のまとめ取得コントローラ
+func (ctrl Controller) GetYearInReview(c *gin.Context) {
+	var s service.Service
+	report, statusCode, err := s.GetYearInReview(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.YearInReview{},
+		}
+		c.JSON(int(statusCode), response)
+	} else if report.Data != nil {
+		c.Data(http.StatusOK, report.ContentType, report.Data)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   report.Summary,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
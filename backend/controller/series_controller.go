@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	service "github.com/KoyoMiyazaki/Book-Reviewer/service"
+	"github.com/gin-gonic/gin"
+)
+
+// シリーズ詳細取得コントローラ
+func (ctrl Controller) GetSeries(c *gin.Context) {
+	var s service.Service
+	series, statusCode, err := s.GetSeries(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.SeriesResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   series,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// シリーズ登録コントローラ
+func (ctrl Controller) CreateSeries(c *gin.Context) {
+	var s service.Service
+	newSeries, statusCode, err := s.CreateSeries(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.SeriesResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   newSeries,
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// シリーズの巻登録コントローラ
+func (ctrl Controller) AddSeriesEntry(c *gin.Context) {
+	var s service.Service
+	series, statusCode, err := s.AddSeriesEntry(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.SeriesResponse{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   series,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// シリーズの巻削除コントローラ
+func (ctrl Controller) DeleteSeriesEntry(c *gin.Context) {
+	var s service.Service
+	statusCode, err := s.DeleteSeriesEntry(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   entity.ResponseSeriesEntry{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   "deleted successfully",
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// シリーズの次に読む巻の一覧取得コントローラ
+func (ctrl Controller) GetNextInSeries(c *gin.Context) {
+	var s service.Service
+	nextEntries, statusCode, err := s.GetNextInSeries(c)
+
+	if err != nil {
+		response := Response{
+			Status: "error",
+			Error:  err.Error(),
+			Data:   []entity.NextInSeries{},
+		}
+		c.JSON(int(statusCode), response)
+	} else {
+		response := Response{
+			Status: "success",
+			Error:  "",
+			Data:   nextEntries,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,300 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 表紙画像のサイズ(幅のピクセル数)
+var Sizes = map[string]int{
+	"small":  128,
+	"medium": 256,
+	"large":  512,
+}
+
+// 表紙画像のサイズが指定されない場合のサイズ
+const DefaultSize = "medium"
+
+// 環境変数が未設定の場合の設定
+const (
+	defaultMaxBytes     = 5 << 20 // 5MB
+	defaultDir          = "covers"
+	defaultAllowedHosts = "books.google.com,books.googleusercontent.com,covers.openlibrary.org"
+)
+
+// デコードを許可する画像の大きさ(小さなファイルで巨大な画像を宣言し、大量のメモリを確保させる攻撃を防ぐ)
+const (
+	maxImageDimension = 10000    // 幅、高さそれぞれの最大ピクセル数
+	maxImagePixels    = 25000000 // 幅×高さの最大ピクセル数
+)
+
+// リダイレクトを追う最大回数
+const maxRedirects = 5
+
+// 表紙画像として受け付けるContent-Type
+var allowedContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// 表紙画像の取得に失敗した場合のエラー
+var (
+	ErrInvalidURL   = errors.New("cover url is not allowed")
+	ErrInvalidImage = errors.New("cover is not a valid image")
+	ErrTooLarge     = errors.New("cover image is too large")
+	ErrTooManyHops  = errors.New("cover request was redirected too many times")
+)
+
+// 表紙画像を取得し、リサイズした画像をストアにキャッシュするプロキシ
+type Proxy struct {
+	Store        Store
+	Client       *http.Client
+	MaxBytes     int64    // 取得する画像の最大サイズ
+	AllowedHosts []string // 取得を許可するホスト(サブドメインを含む)
+}
+
+// リサイズ済みの表紙画像
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+}
+
+var (
+	defaultProxy *Proxy
+	defaultMutex sync.Mutex
+)
+
+// アプリケーション全体で用いるプロキシを返す(初回呼び出し時に環境変数の設定から生成する)
+func Default() *Proxy {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+
+	if defaultProxy == nil {
+		defaultProxy = NewFromEnv()
+	}
+	return defaultProxy
+}
+
+// アプリケーション全体で用いるプロキシを差し替える
+func SetDefault(p *Proxy) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+
+	defaultProxy = p
+}
+
+// 環境変数の設定からプロキシを生成する
+//   - COVER_STORE: 表紙画像の保存先("disk"、または"s3")
+//   - COVER_DIR: diskの場合の保存先ディレクトリ
+//   - COVER_S3_ENDPOINT、COVER_S3_BUCKET、COVER_S3_REGION、COVER_S3_ACCESS_KEY、COVER_S3_SECRET_KEY: s3の場合の接続情報
+//   - COVER_MAX_BYTES: 取得する画像の最大サイズ(バイト)
+//   - COVER_ALLOWED_HOSTS: 取得を許可するホストをカンマ区切りで指定
+func NewFromEnv() *Proxy {
+	var store Store
+	switch os.Getenv("COVER_STORE") {
+	case "s3":
+		store = &S3Store{
+			Endpoint:  os.Getenv("COVER_S3_ENDPOINT"),
+			Bucket:    os.Getenv("COVER_S3_BUCKET"),
+			Region:    os.Getenv("COVER_S3_REGION"),
+			AccessKey: os.Getenv("COVER_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("COVER_S3_SECRET_KEY"),
+			Client:    &http.Client{Timeout: 10 * time.Second},
+		}
+	default:
+		dir := os.Getenv("COVER_DIR")
+		if dir == "" {
+			dir = defaultDir
+		}
+		store = DiskStore{Dir: dir}
+	}
+
+	maxBytes := int64(defaultMaxBytes)
+	if value, err := strconv.ParseInt(os.Getenv("COVER_MAX_BYTES"), 10, 64); err == nil && value > 0 {
+		maxBytes = value
+	}
+	allowedHosts := os.Getenv("COVER_ALLOWED_HOSTS")
+	if allowedHosts == "" {
+		allowedHosts = defaultAllowedHosts
+	}
+
+	return &Proxy{
+		Store:        store,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		MaxBytes:     maxBytes,
+		AllowedHosts: strings.Split(allowedHosts, ","),
+	}
+}
+
+// 表紙画像のURLと幅を指定し、リサイズした画像を返す
+// キャッシュ済みの場合はストアから返し、無い場合は取得、リサイズしてストアに保存する
+func (p *Proxy) Get(ctx context.Context, coverURL string, width int) (Image, error) {
+	coverURL, err := p.normalizeURL(coverURL)
+	if err != nil {
+		return Image{}, err
+	}
+	key := cacheKey(coverURL, width)
+
+	data, err := p.Store.Get(ctx, key)
+	if err == nil {
+		return Image{Data: data, ContentType: "image/jpeg", ETag: key}, nil
+	}
+	if !errors.Is(err, ErrNotExist) {
+		return Image{}, err
+	}
+
+	// 取得してリサイズ
+	original, err := p.fetch(ctx, coverURL)
+	if err != nil {
+		return Image{}, err
+	}
+	data, err = resizeJPEG(original, width)
+	if err != nil {
+		return Image{}, err
+	}
+
+	if err := p.Store.Put(ctx, key, data); err != nil {
+		return Image{}, err
+	}
+	return Image{Data: data, ContentType: "image/jpeg", ETag: key}, nil
+}
+
+// 取得を許可するURLか検証し、httpsのURLに変換する
+func (p *Proxy) normalizeURL(coverURL string) (string, error) {
+	u, err := url.Parse(coverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	allowed := false
+	for _, allowedHost := range p.AllowedHosts {
+		allowedHost = strings.ToLower(strings.TrimSpace(allowedHost))
+		if allowedHost != "" && (host == allowedHost || strings.HasSuffix(host, "."+allowedHost)) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", ErrInvalidURL
+	}
+
+	// 混在コンテンツとならないよう、httpのURLはhttpsで取得する
+	u.Scheme = "https"
+	return u.String(), nil
+}
+
+// リダイレクト先も、許可されたホストか検証し、httpsで取得する
+func (p *Proxy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return ErrTooManyHops
+	}
+	redirectURL, err := p.normalizeURL(req.URL.String())
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+	req.URL = u
+	req.Host = u.Host
+	return nil
+}
+
+// 表紙画像を取得し、Content-Typeとサイズを検証する
+func (p *Proxy) fetch(ctx context.Context, coverURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coverURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 許可されていないホストにリダイレクトされないよう、リダイレクトの度に検証する
+	client := *p.Client
+	client.CheckRedirect = p.checkRedirect
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover request failed with status %d", res.StatusCode)
+	}
+	if !isAllowedContentType(res.Header.Get("Content-Type")) {
+		return nil, ErrInvalidImage
+	}
+	if res.ContentLength > p.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, p.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > p.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	// Content-Typeヘッダだけでなく、内容からも画像であることを確認する
+	if !isAllowedContentType(http.DetectContentType(data)) {
+		return nil, ErrInvalidImage
+	}
+	return data, nil
+}
+
+// 画像をデコードし、指定した幅に縮小してJPEG形式で返す(指定した幅より小さい画像は拡大しない)
+func resizeJPEG(data []byte, width int) ([]byte, error) {
+	// デコードする前に、ヘッダから画像の大きさを検証する
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension || config.Width*config.Height > maxImagePixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resize(src, width), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 許可されたContent-Typeか判定する(パラメータは無視する)
+func isAllowedContentType(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.ToLower(strings.Split(contentType, ";")[0]))
+	for _, allowed := range allowedContentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// 表紙画像のURLと幅から、ストアのキーを生成する
+func cacheKey(coverURL string, width int) string {
+	sum := sha256.Sum256([]byte(coverURL))
+	return fmt.Sprintf("covers/%s/%d.jpg", hex.EncodeToString(sum[:]), width)
+}
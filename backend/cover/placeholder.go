@@ -0,0 +1,77 @@
+package cover
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+)
+
+// プレースホルダの背景色(タイトルから決定する)
+var placeholderColors = []string{"#5c6bc0", "#26a69a", "#ef6c00", "#8d6e63", "#ab47bc", "#42a5f5", "#66bb6a", "#ec407a"}
+
+// プレースホルダに表示するタイトルの1行の文字数、最大の行数
+const (
+	placeholderLineLength = 12
+	placeholderMaxLines   = 4
+)
+
+// 表紙画像が無い場合の、タイトルと著者を表示するプレースホルダ画像(SVG)を生成する
+// 縦横比は書籍の表紙に合わせて2:3とする
+func Placeholder(title, author string, width int) Image {
+	height := width * 3 / 2
+	hash := fnv.New32a()
+	hash.Write([]byte(title + "\x00" + author))
+	color := placeholderColors[hash.Sum32()%uint32(len(placeholderColors))]
+
+	fontSize := width / 12
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`, color)
+	fmt.Fprintf(&svg, `<text x="50%%" y="%d" fill="#ffffff" font-family="sans-serif" font-size="%d" font-weight="bold" text-anchor="middle">`, height/3, fontSize)
+	for i, line := range wrapText(title, placeholderLineLength, placeholderMaxLines) {
+		dy := 0
+		if i > 0 {
+			dy = fontSize * 5 / 4
+		}
+		fmt.Fprintf(&svg, `<tspan x="50%%" dy="%d">%s</tspan>`, dy, html.EscapeString(line))
+	}
+	svg.WriteString(`</text>`)
+	fmt.Fprintf(&svg, `<text x="50%%" y="%d" fill="#ffffff" font-family="sans-serif" font-size="%d" text-anchor="middle">%s</text>`, height*5/6, fontSize*3/4, html.EscapeString(truncate(author, placeholderLineLength*2)))
+	svg.WriteString(`</svg>`)
+
+	hash.Write([]byte(fmt.Sprintf("\x00%d", width)))
+	return Image{
+		Data:        []byte(svg.String()),
+		ContentType: "image/svg+xml",
+		ETag:        fmt.Sprintf("placeholder-%x", hash.Sum32()),
+	}
+}
+
+// 文字列を指定した文字数ごとに改行する(行数を超える場合は末尾を省略する)
+func wrapText(s string, lineLength, maxLines int) []string {
+	runes := []rune(strings.TrimSpace(s))
+	var lines []string
+	for len(runes) > 0 && len(lines) < maxLines {
+		n := lineLength
+		if n > len(runes) {
+			n = len(runes)
+		}
+		lines = append(lines, string(runes[:n]))
+		runes = runes[n:]
+	}
+	if len(runes) > 0 {
+		last := []rune(lines[len(lines)-1])
+		lines[len(lines)-1] = string(last[:len(last)-1]) + "…"
+	}
+	return lines
+}
+
+// 文字列を指定した文字数に切り詰める
+func truncate(s string, length int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,56 @@
+package cover
+
+import (
+	"image"
+	"image/color"
+)
+
+// 画像を指定した幅に縮小する(縦横比は維持し、指定した幅より小さい画像は拡大しない)
+// 縮小先の1ピクセルに対応する縮小元の領域の平均を取る(透過部分は白で塗りつぶす)
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= width || srcWidth == 0 {
+		width = srcWidth
+	}
+	height := srcHeight * width / maxInt(srcWidth, 1)
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := maxInt(bounds.Min.Y+(y+1)*srcHeight/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := maxInt(bounds.Min.X+(x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					// 白の背景に合成する
+					r += uint64(cr + (0xffff - ca))
+					g += uint64(cg + (0xffff - ca))
+					b += uint64(cb + (0xffff - ca))
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -29,7 +29,13 @@ func Init() error {
 	pgDBname := os.Getenv("PG_DBNAME")
 	pgPort := os.Getenv("PG_PORT")
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Tokyo", pgHost, pgUser, pgPass, pgDBname, pgPort)
+	// 日付の集計はユーザごとのタイムゾーンで行うため、接続のタイムゾーンは既定でUTCとする
+	pgTimeZone := os.Getenv("PG_TIMEZONE")
+	if pgTimeZone == "" {
+		pgTimeZone = "UTC"
+	}
+
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s", pgHost, pgUser, pgPass, pgDBname, pgPort, pgTimeZone)
 	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect database")
@@ -63,11 +69,96 @@ func autoMigrate() error {
 	if err := db.AutoMigrate(&entity.User{}); err != nil {
 		return err
 	}
+	// 書籍の同一性はISBN等で判定するため、タイトルと著者の一意制約を削除する
+	// (同じタイトルと著者の、異なる版の書籍を登録できるようにする)
+	if db.Migrator().HasIndex(&entity.Book{}, "title_and_author_unique_idx") {
+		if err := db.Migrator().DropIndex(&entity.Book{}, "title_and_author_unique_idx"); err != nil {
+			return err
+		}
+	}
 	if err := db.AutoMigrate(&entity.Book{}); err != nil {
 		return err
 	}
+	// 同じ書籍が同時に登録されないよう、ISBN13と書籍メタデータプロバイダのIDに一意制約を設定する(空の場合は除く)
+	// 登録済みの書籍に重複がある場合は作成できないため、書籍の統合後に作成する
+	for _, index := range []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS book_isbn13_unique_idx ON books (isbn13) WHERE isbn13 <> ''",
+		"CREATE UNIQUE INDEX IF NOT EXISTS book_provider_and_volume_id_unique_idx ON books (provider, volume_id) WHERE volume_id <> ''",
+	} {
+		if err := db.Exec(index).Error; err != nil {
+			log.Printf("failed to create unique index on books (merge duplicate books first): %v", err)
+		}
+	}
+	if err := db.AutoMigrate(&entity.Genre{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.BookGenre{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.Work{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.Author{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.BookAuthor{}); err != nil {
+		return err
+	}
 	if err := db.AutoMigrate(&entity.Review{}); err != nil {
 		return err
 	}
+	if err := db.AutoMigrate(&entity.Series{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.SeriesEntry{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.Quote{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.ImportJob{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.ImportJobRow{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.BookMetadataCache{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.BookEdit{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.BookChange{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.DuplicateCandidate{}); err != nil {
+		return err
+	}
+	// 通知済みかを、最後に通知した価格が0でないかで判定していたため、通知済みの列の追加時に値を設定する
+	// (価格が0の場合に通知済みと判定できず、繰り返し通知していた)
+	addNotified := db.Migrator().HasTable(&entity.PriceWatch{}) && !db.Migrator().HasColumn(&entity.PriceWatch{}, "Notified")
+	if err := db.AutoMigrate(&entity.PriceWatch{}); err != nil {
+		return err
+	}
+	if addNotified {
+		if err := db.Model(&entity.PriceWatch{}).Where("last_notified_price <> 0").Update("notified", true).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.AutoMigrate(&entity.PriceHistory{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.Notification{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.Goal{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.ReadingActivity{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&entity.MonthlyReviewStat{}); err != nil {
+		return err
+	}
 	return nil
 }
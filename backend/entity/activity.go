@@ -0,0 +1,42 @@
+package entity
+
+// 読書記録の種類
+const (
+	ReadingActivityStatusChanged = "statusChanged" // 読書ステータスの変更
+	ReadingActivityPagesLogged   = "pagesLogged"   // 読んだページ数の記録
+)
+
+// 読書記録モデルエンティティ
+// レビューの読書ステータス、読んだページ数が変わるたびに記録する(レビューの削除後も記録は残す)
+type ReadingActivity struct {
+	ID            uint   `gorm:"primaryKey"`
+	Type          string `gorm:"type:varchar;not null"`
+	ReadingStatus string `gorm:"type:varchar"` // 変更後の読書ステータス
+	Pages         uint   // 前回の記録から増えたページ数
+	CreatedAt     int64  `gorm:"autoCreateTime;index"`
+	ReviewID      uint   `gorm:"index"`
+	UserID        uint   `gorm:"index"`
+	User          User   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 読書の活動状況レスポンス用構造体
+type ReadingActivityResponse struct {
+	From               string          `json:"from"`               // "2006-01-02"形式
+	To                 string          `json:"to"`                 // "2006-01-02"形式
+	TimeZone           string          `json:"timeZone"`           // 日付の集計に用いたタイムゾーン
+	Days               []DailyActivity `json:"days"`               // 日別の活動(活動の無い日を含む)
+	MaxCount           int64           `json:"maxCount"`           // 期間内で最も活動の多い日の活動数(ヒートマップの色分け用)
+	CurrentStreak      int             `json:"currentStreak"`      // 今日(今日の活動が無い場合は昨日)まで連続して活動した日数
+	LongestStreak      int             `json:"longestStreak"`      // これまでで最も長く連続して活動した日数
+	LongestStreakStart string          `json:"longestStreakStart"` // 最長の連続記録の開始日(活動が無い場合は空文字)
+	LongestStreakEnd   string          `json:"longestStreakEnd"`   // 最長の連続記録の終了日(活動が無い場合は空文字)
+}
+
+// レスポンス用日別の活動構造体
+type DailyActivity struct {
+	Date           string `json:"date"` // "2006-01-02"形式
+	ReviewsCreated int64  `json:"reviewsCreated"`
+	StatusChanges  int64  `json:"statusChanges"`
+	PagesLogged    int64  `json:"pagesLogged"`
+	Count          int64  `json:"count"` // レビューの登録数と読書記録の件数の合計
+}
@@ -0,0 +1,73 @@
+package entity
+
+// 書籍に対する著者の役割
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleTranslator  = "translator"
+	AuthorRoleIllustrator = "illustrator"
+	AuthorRoleEditor      = "editor"
+)
+
+// 著者モデルエンティティ
+type Author struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar;not null;uniqueIndex"`
+	CanonicalID uint   `gorm:"index"` // 別名の場合は、正式な著者のID(例: "Haruki Murakami"は"村上春樹"の別名)
+	CreatedAt   int64  `gorm:"autoCreateTime"`
+	UpdatedAt   int64  `gorm:"autoUpdateTime"`
+}
+
+// 書籍と著者の関連モデルエンティティ(1冊の書籍に、役割の異なる複数の著者が関わる)
+type BookAuthor struct {
+	BookID   uint   `gorm:"primaryKey"`
+	Book     Book   `gorm:"constraint:OnDelete:CASCADE"`
+	AuthorID uint   `gorm:"primaryKey;index"`
+	Author   Author `gorm:"constraint:OnDelete:CASCADE"`
+	Role     string `gorm:"type:varchar;primaryKey"`
+	Position uint   // 書籍内での著者の表示順
+}
+
+// 書籍の著者登録リクエスト用構造体
+type ContributorRequest struct {
+	Name string `json:"name" validate:"required"`
+	Role string `json:"role" validate:"omitempty,oneof=author translator illustrator editor"`
+}
+
+// レスポンス用の書籍の著者構造体
+type ResponseContributor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// 著者の別名登録リクエスト用構造体
+type AddAuthorAliasRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// レスポンス用の著者の別名構造体
+type ResponseAuthorAlias struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	CanonicalID uint   `json:"canonicalId"`
+}
+
+// 著者詳細レスポンス用構造体
+type AuthorResponse struct {
+	ID      uint             `json:"id"`
+	Name    string           `json:"name"`
+	Aliases []string         `json:"aliases"`
+	Books   []AuthorBook     `json:"books"`   // 別名で登録された書籍を含む
+	Reviews []ResponseReview `json:"reviews"` // ログインユーザの、著者の書籍に対するレビュー
+}
+
+// 著者詳細レスポンス用の書籍構造体
+type AuthorBook struct {
+	ID            uint   `json:"id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	ThumbnailLink string `json:"thumbnailLink"`
+	PublishedDate string `json:"publishedDate"`
+	NumOfPages    uint   `json:"numOfPages"`
+	Role          string `json:"role"`
+}
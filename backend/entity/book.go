@@ -1,20 +1,31 @@
 package entity
 
 // Bookモデルエンティティ
+// ISBN13、書籍メタデータプロバイダのIDには、空の場合を除く一意制約を設定する(db.autoMigrateで作成する部分インデックス)
 type Book struct {
 	ID            uint   `gorm:"primaryKey"`
-	Title         string `gorm:"type:varchar;not null;uniqueIndex:title_and_author_unique_idx"`
-	Author        string `gorm:"type:varchar;not null;uniqueIndex:title_and_author_unique_idx"`
+	Title         string `gorm:"type:varchar;not null;index:title_and_author_idx"`
+	Author        string `gorm:"type:varchar;not null;index:title_and_author_idx"`
 	ThumbnailLink string `gorm:"type:varchar"`
 	PublishedDate string `gorm:"type:varchar"`
 	NumOfPages    uint
-	CreatedAt     int64 `gorm:"autoCreateTime"`
-	UpdatedAt     int64 `gorm:"autoUpdateTime"`
+	ISBN10        string `gorm:"type:varchar;index"`
+	ISBN13        string `gorm:"type:varchar;index"`
+	Provider      string `gorm:"type:varchar;index:provider_and_volume_id_idx"` // 書籍メタデータプロバイダ名
+	VolumeID      string `gorm:"type:varchar;index:provider_and_volume_id_idx"` // 書籍メタデータプロバイダでのID
+	WorkID        uint   `gorm:"index"`                                         // 作品ID(同じ作品の版は同じ作品IDを持つ)
+	Categories    string `gorm:"type:text"`                                     // 書籍メタデータプロバイダでの分類(カンマ区切り)
+	Description   string `gorm:"type:text"`
+	Publisher     string `gorm:"type:varchar"`
+	Language      string `gorm:"type:varchar"`
+	CreatedAt     int64  `gorm:"autoCreateTime"`
+	UpdatedAt     int64  `gorm:"autoUpdateTime"`
 }
 
 // Google Books APIのレスポンス用構造体
 type ResponseFromGoogleBooks struct {
-	Items []BookDataFromGoogleBooks `json:"items"`
+	TotalItems int                       `json:"totalItems"`
+	Items      []BookDataFromGoogleBooks `json:"items"`
 }
 
 // Google Books APIのレスポンス用構造体(items配下)
@@ -26,11 +37,22 @@ type BookDataFromGoogleBooks struct {
 
 // Google Books APIのレスポンス用構造体(volumeInfo配下)
 type VolumeInfoFromGoogleBooks struct {
-	Title         string                    `json:"title"`
-	Authors       []string                  `json:"authors"`
-	PublishedDate string                    `json:"publishedDate"`
-	ImageLinks    ImageLinksFromGoogleBooks `json:"imageLinks"`
-	PageCount     uint                      `json:"pageCount"`
+	Title               string                              `json:"title"`
+	Authors             []string                            `json:"authors"`
+	PublishedDate       string                              `json:"publishedDate"`
+	ImageLinks          ImageLinksFromGoogleBooks           `json:"imageLinks"`
+	PageCount           uint                                `json:"pageCount"`
+	IndustryIdentifiers []IndustryIdentifierFromGoogleBooks `json:"industryIdentifiers"`
+	Categories          []string                            `json:"categories"`
+	Description         string                              `json:"description"`
+	Publisher           string                              `json:"publisher"`
+	Language            string                              `json:"language"`
+}
+
+// Google Books APIのレスポンス用構造体(industryIdentifiers配下)
+type IndustryIdentifierFromGoogleBooks struct {
+	Type       string `json:"type"` // ISBN_10、ISBN_13、OTHERのいずれか
+	Identifier string `json:"identifier"`
 }
 
 // Google Books APIのレスポンス用構造体(imageLinks配下)
@@ -52,19 +74,76 @@ type RetailPriceFromGoogleBooks struct {
 
 // 書籍検索レスポンス用構造体
 type SearchResponse struct {
-	ResponseBooks []ResponseBook `json:"data"`
+	ResponseBooks []ResponseBook `json:"items"`
+	TotalItems    int            `json:"totalItems"`
+	TotalPages    int            `json:"totalPages"`
+	Page          int            `json:"page"`
+	PageSize      int            `json:"pageSize"`
 }
 
 // 書籍検索レスポンス用の書籍構造体
 type ResponseBook struct {
-	ID            string `json:"id"` // Google Books API のIDフィールド(string型)を使用するため、型はstring
-	Title         string `json:"title"`
-	Author        string `json:"author"`
-	ThumbnailLink string `json:"thumbnailLink"`
-	PublishedDate string `json:"publishedDate"`
-	NumOfPages    uint   `json:"numOfPages"`
-	IsReviewed    bool   `json:"isReviewed"`
-	IsForSale     bool   `json:"isForSale"`
-	Price         uint   `json:"price"`
-	BuyLink       string `json:"buyLink"`
+	ID            string   `json:"id"` // 書籍メタデータプロバイダのIDフィールド(string型)を使用するため、型はstring
+	Provider      string   `json:"provider"`
+	Title         string   `json:"title"`
+	Author        string   `json:"author"`
+	ThumbnailLink string   `json:"thumbnailLink"`
+	PublishedDate string   `json:"publishedDate"`
+	NumOfPages    uint     `json:"numOfPages"`
+	ISBN10        string   `json:"isbn10"`
+	ISBN13        string   `json:"isbn13"`
+	Categories    []string `json:"categories"`
+	Description   string   `json:"description"`
+	Publisher     string   `json:"publisher"`
+	Language      string   `json:"language"`
+	IsReviewed    bool     `json:"isReviewed"`
+	IsForSale     bool     `json:"isForSale"`
+	Price         uint     `json:"price"`
+	BuyLink       string   `json:"buyLink"`
+}
+
+// 書籍詳細レスポンス用構造体
+type BookDetailResponse struct {
+	ID            uint                  `json:"id"`
+	Title         string                `json:"title"`
+	Author        string                `json:"author"`
+	ThumbnailLink string                `json:"thumbnailLink"`
+	CoverLink     string                `json:"coverLink"` // 表紙画像プロキシのURL
+	PublishedDate string                `json:"publishedDate"`
+	NumOfPages    uint                  `json:"numOfPages"`
+	ISBN10        string                `json:"isbn10"`
+	ISBN13        string                `json:"isbn13"`
+	Provider      string                `json:"provider"`
+	VolumeID      string                `json:"volumeId"`
+	WorkID        uint                  `json:"workId"`
+	Description   string                `json:"description"`
+	Publisher     string                `json:"publisher"`
+	Language      string                `json:"language"`
+	Categories    []string              `json:"categories"`
+	Genres        []ResponseGenre       `json:"genres"`
+	Contributors  []ResponseContributor `json:"contributors"`
+	Community     BookCommunityStats    `json:"community"` // 作品の全ての版の公開レビューの集計
+	MyReview      *ResponseReview       `json:"myReview"`  // 未ログイン、または未レビューの場合はnull
+}
+
+// 書籍詳細、作品詳細レスポンス用構造体(全ユーザの公開レビューの集計)
+type BookCommunityStats struct {
+	AverageRating   float64       `json:"averageRating"`
+	NumOfRatings    int64         `json:"numOfRatings"`
+	RatingHistogram []RatingCount `json:"ratingHistogram"`
+	NumOfReaders    int64         `json:"numOfReaders"`
+	NumOfReading    int64         `json:"numOfReading"`
+	PopularTags     []TagCount    `json:"popularTags"`
+}
+
+// 評価ごとのレビュー件数
+type RatingCount struct {
+	Rating float64 `json:"rating"`
+	Count  int64   `json:"count"`
+}
+
+// タグごとのレビュー件数
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
 }
@@ -0,0 +1,19 @@
+package entity
+
+// 書籍メタデータのキャッシュモデルエンティティ
+type BookMetadataCache struct {
+	Key       string `gorm:"type:varchar(64);primaryKey"`
+	Value     []byte `gorm:"type:bytea;not null"`
+	ExpiresAt int64  `gorm:"not null;index"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+}
+
+// 書籍メタデータのキャッシュの統計情報レスポンス用構造体
+type BookCacheStats struct {
+	Hits      int64   `json:"hits"`
+	StoreHits int64   `json:"storeHits"`
+	Misses    int64   `json:"misses"`
+	Bypassed  int64   `json:"bypassed"`
+	Entries   int     `json:"entries"`
+	HitRate   float64 `json:"hitRate"`
+}
@@ -0,0 +1,126 @@
+package entity
+
+// 書籍データの修正提案のステータス
+const (
+	BookEditStatusPending  = "pending"
+	BookEditStatusApproved = "approved"
+	BookEditStatusRejected = "rejected"
+)
+
+// 書籍データの変更履歴の種類
+const (
+	BookChangeActionCreate = "create" // 書籍の新規登録
+	BookChangeActionEdit   = "edit"   // 修正提案の承認による変更
+	BookChangeActionMerge  = "merge"  // 重複した書籍の統合による変更
+)
+
+// 書籍データの修正提案モデルエンティティ
+// 修正しない項目はnullとする
+type BookEdit struct {
+	ID               uint    `gorm:"primaryKey"`
+	Title            *string `gorm:"type:varchar"`
+	Author           *string `gorm:"type:varchar"`
+	ThumbnailLink    *string `gorm:"type:varchar"`
+	PublishedDate    *string `gorm:"type:varchar"`
+	NumOfPages       *uint
+	ISBN             *string `gorm:"type:varchar"` // ISBN-10、またはISBN-13(空文字の場合はISBNを削除する)
+	Publisher        *string `gorm:"type:varchar"`
+	Language         *string `gorm:"type:varchar"`
+	Description      *string `gorm:"type:text"`
+	Comment          string  `gorm:"type:text"` // 修正の理由
+	Status           string  `gorm:"type:varchar;not null;default:pending;index"`
+	ModeratorID      uint    // 承認、または却下したモデレータ
+	ModeratorComment string  `gorm:"type:text"`
+	ModeratedAt      int64
+	CreatedAt        int64 `gorm:"autoCreateTime"`
+	UpdatedAt        int64 `gorm:"autoUpdateTime"`
+	BookID           uint  `gorm:"index"`
+	Book             Book  `gorm:"constraint:OnDelete:CASCADE"`
+	UserID           uint  // 修正を提案したユーザ
+	User             User  `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 書籍データの変更履歴モデルエンティティ
+// 変更された項目ごとに1件記録する(ユーザの削除後も履歴は残すため、ユーザとの関連は持たない)
+type BookChange struct {
+	ID          uint   `gorm:"primaryKey"`
+	Action      string `gorm:"type:varchar;not null"`
+	Field       string `gorm:"type:varchar;not null"` // 項目名(レスポンスのJSONのキー)
+	OldValue    string `gorm:"type:text"`
+	NewValue    string `gorm:"type:text"`
+	UserID      uint   // 書籍を登録、修正を提案、または統合したユーザ
+	ModeratorID uint   // 修正提案を承認、または書籍を統合したモデレータ
+	BookEditID  uint   // 修正提案による変更の場合の修正提案ID
+	CreatedAt   int64  `gorm:"autoCreateTime"`
+	BookID      uint   `gorm:"index"`
+	Book        Book   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 書籍登録リクエスト用構造体
+type CreateBookRequest struct {
+	Title         string               `json:"title" validate:"required"`
+	Author        string               `json:"author" validate:"required"`
+	Contributors  []ContributorRequest `json:"contributors" validate:"dive"` // 未指定の場合は著者文字列から登録する
+	ThumbnailLink string               `json:"thumbnailLink" validate:"omitempty,url"`
+	PublishedDate string               `json:"publishedDate"` // "2006"、"2006-01"、"2006-01-02"のいずれかの形式
+	NumOfPages    uint                 `json:"numOfPages"`
+	ISBN          string               `json:"isbn"` // ISBN-10、またはISBN-13(ハイフン可)
+	Categories    []string             `json:"categories"`
+	Description   string               `json:"description"`
+	Publisher     string               `json:"publisher"`
+	Language      string               `json:"language"`
+	WorkID        uint                 `json:"workId"` // 既存の作品の版として登録する場合の作品ID
+}
+
+// 書籍データの修正提案リクエスト用構造体(修正しない項目は省略する)
+type SuggestBookEditRequest struct {
+	Title         *string `json:"title" validate:"omitempty,min=1"`
+	Author        *string `json:"author" validate:"omitempty,min=1"`
+	ThumbnailLink *string `json:"thumbnailLink" validate:"omitempty,url"`
+	PublishedDate *string `json:"publishedDate"`
+	NumOfPages    *uint   `json:"numOfPages"`
+	ISBN          *string `json:"isbn"`
+	Publisher     *string `json:"publisher"`
+	Language      *string `json:"language"`
+	Description   *string `json:"description"`
+	Comment       string  `json:"comment"`
+}
+
+// 書籍データの修正提案の承認、却下リクエスト用構造体
+type ModerateBookEditRequest struct {
+	Comment string `json:"comment"`
+}
+
+// レスポンス用書籍データの修正提案構造体
+type ResponseBookEdit struct {
+	ID               uint                `json:"id"`
+	BookID           uint                `json:"bookId"`
+	BookTitle        string              `json:"bookTitle"`
+	UserName         string              `json:"userName"`
+	Changes          []ResponseBookField `json:"changes"` // 承認済みの場合は反映した変更、それ以外の場合は現在の書籍データとの差分
+	Comment          string              `json:"comment"`
+	Status           string              `json:"status"`
+	ModeratorComment string              `json:"moderatorComment"`
+	ModeratedAt      int64               `json:"moderatedAt"`
+	CreatedAt        int64               `json:"createdAt"`
+}
+
+// レスポンス用書籍データの項目の変更構造体
+type ResponseBookField struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// レスポンス用書籍データの変更履歴構造体
+type ResponseBookChange struct {
+	ID            uint   `json:"id"`
+	Action        string `json:"action"`
+	Field         string `json:"field"`
+	OldValue      string `json:"oldValue"`
+	NewValue      string `json:"newValue"`
+	UserName      string `json:"userName"`      // 削除済みのユーザの場合は空文字
+	ModeratorName string `json:"moderatorName"` // 削除済みのユーザの場合は空文字
+	BookEditID    uint   `json:"bookEditId"`
+	CreatedAt     int64  `json:"createdAt"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+// 表紙画像レスポンス用構造体
+type CoverImage struct {
+	Data         []byte
+	ContentType  string
+	ETag         string
+	CacheControl string
+	NotModified  bool // If-None-Matchに一致した場合はtrue(本文を返さない)
+}
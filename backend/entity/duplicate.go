@@ -0,0 +1,67 @@
+package entity
+
+// 重複候補のステータス
+const (
+	DuplicateStatusPending   = "pending"
+	DuplicateStatusDismissed = "dismissed" // 重複ではないと判断されたもの(以降の検出でも再度候補としない)
+)
+
+// 重複と判定した理由
+const (
+	DuplicateReasonISBN        = "isbn"         // ISBNが一致
+	DuplicateReasonVolumeID    = "volumeId"     // 書籍メタデータプロバイダのIDが一致
+	DuplicateReasonTitle       = "title"        // 正規化したタイトルが一致
+	DuplicateReasonMainTitle   = "mainTitle"    // 副題を除いたタイトルが一致
+	DuplicateReasonSimilar     = "similarTitle" // タイトルが類似
+	DuplicateReasonAuthor      = "author"       // 正規化した著者名が一致
+	DuplicateReasonAuthorAlias = "authorAlias"  // 著者の別名が一致
+)
+
+// 書籍の重複候補モデルエンティティ
+// 書籍の組は、IDの小さい書籍をBookID、大きい書籍をOtherBookIDとする(統合した場合は、書籍の削除とともに削除される)
+type DuplicateCandidate struct {
+	ID          uint    `gorm:"primaryKey"`
+	Score       float64 `gorm:"not null"`     // 類似度(0〜1)
+	Reasons     string  `gorm:"type:varchar"` // 重複と判定した理由(カンマ区切り)
+	Status      string  `gorm:"type:varchar;not null;default:pending;index"`
+	ScannedAt   int64   // 最後に重複候補として検出した日時
+	CreatedAt   int64   `gorm:"autoCreateTime"`
+	UpdatedAt   int64   `gorm:"autoUpdateTime"`
+	BookID      uint    `gorm:"uniqueIndex:duplicate_pair_unique_idx"`
+	Book        Book    `gorm:"constraint:OnDelete:CASCADE"`
+	OtherBookID uint    `gorm:"uniqueIndex:duplicate_pair_unique_idx"`
+	OtherBook   Book    `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 書籍の統合リクエスト用構造体
+type MergeBooksRequest struct {
+	DuplicateID uint `json:"duplicateId" validate:"required"` // 統合して削除する書籍のID
+}
+
+// レスポンス用書籍の重複候補構造体
+type ResponseDuplicateCandidate struct {
+	ID        uint          `json:"id"`
+	Score     float64       `json:"score"`
+	Reasons   []string      `json:"reasons"`
+	Status    string        `json:"status"`
+	Book      DuplicateBook `json:"book"`
+	OtherBook DuplicateBook `json:"otherBook"`
+}
+
+// 重複候補レスポンス用の書籍構造体
+type DuplicateBook struct {
+	ID            uint   `json:"id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	ThumbnailLink string `json:"thumbnailLink"`
+	PublishedDate string `json:"publishedDate"`
+	ISBN13        string `json:"isbn13"`
+	NumOfReviews  int64  `json:"numOfReviews"`
+}
+
+// 書籍の統合レスポンス用構造体
+type MergeBooksResponse struct {
+	BookID            uint  `json:"bookId"`       // 統合先の書籍のID
+	MergedBookID      uint  `json:"mergedBookId"` // 統合して削除した書籍のID
+	NumOfMovedReviews int64 `json:"numOfMovedReviews"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+// ジャンルモデルエンティティ
+// 書籍メタデータプロバイダごとに異なる分類を、共通のジャンルに正規化したもの
+type Genre struct {
+	ID        uint   `gorm:"primaryKey"`
+	Slug      string `gorm:"type:varchar;not null;uniqueIndex"` // 例: "mystery"
+	Name      string `gorm:"type:varchar;not null"`             // 例: "ミステリー"
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	UpdatedAt int64  `gorm:"autoUpdateTime"`
+}
+
+// 書籍とジャンルの関連モデルエンティティ
+type BookGenre struct {
+	BookID  uint  `gorm:"primaryKey"`
+	Book    Book  `gorm:"constraint:OnDelete:CASCADE"`
+	GenreID uint  `gorm:"primaryKey;index"`
+	Genre   Genre `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// レスポンス用ジャンル構造体
+type ResponseGenre struct {
+	ID   uint   `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// ジャンルごとの冊数
+type GenreCount struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// 読書目標の指標
+const (
+	GoalMetricBooks = "books" // 読んだ書籍数
+	GoalMetricPages = "pages" // 読んだページ数
+)
+
+// 読書目標の期間の種類
+const (
+	GoalPeriodYear   = "year"
+	GoalPeriodMonth  = "month"
+	GoalPeriodCustom = "custom" // 開始日、終了日を指定
+)
+
+// 読書目標の進捗状況
+const (
+	GoalStatusNotStarted = "notStarted" // 期間の開始前
+	GoalStatusAhead      = "ahead"      // 予定より進んでいる
+	GoalStatusOnTrack    = "onTrack"    // 予定どおり
+	GoalStatusBehind     = "behind"     // 予定より遅れている
+	GoalStatusCompleted  = "completed"  // 目標を達成した
+	GoalStatusFailed     = "failed"     // 目標を達成せずに期間が終了した
+)
+
+// 読書目標モデルエンティティ
+// 期間内に読了したレビューの冊数、またはページ数を進捗とする
+type Goal struct {
+	ID        uint      `gorm:"primaryKey"`
+	Title     string    `gorm:"type:varchar"`
+	Metric    string    `gorm:"type:varchar;not null"`
+	Period    string    `gorm:"type:varchar;not null"`
+	StartDate time.Time `gorm:"type:timestamp;not null"`
+	EndDate   time.Time `gorm:"type:timestamp;not null"` // 期間の最終日(この日を含む)
+	Target    uint      `gorm:"not null"`
+	CreatedAt int64     `gorm:"autoCreateTime"`
+	UpdatedAt int64     `gorm:"autoUpdateTime"`
+	UserID    uint      `gorm:"index"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 読書目標登録リクエスト用構造体
+type CreateGoalRequest struct {
+	Title     string `json:"title"`
+	Metric    string `json:"metric" validate:"required,oneof=books pages"`
+	Period    string `json:"period" validate:"required,oneof=year month custom"`
+	Year      int    `json:"year" validate:"omitempty,min=1,max=9999"` // 期間がyear、monthの場合の対象年(未指定の場合は今年)
+	Month     int    `json:"month" validate:"omitempty,min=1,max=12"`  // 期間がmonthの場合の対象月(未指定の場合は今月)
+	StartDate string `json:"startDate"`                                // 期間がcustomの場合の開始日("2006-01-02"形式)
+	EndDate   string `json:"endDate"`                                  // 期間がcustomの場合の終了日("2006-01-02"形式)
+	Target    uint   `json:"target" validate:"required"`
+}
+
+// 読書目標更新リクエスト用構造体(更新しない項目は省略する)
+type UpdateGoalRequest struct {
+	Title  *string `json:"title"`
+	Target *uint   `json:"target" validate:"omitempty,min=1"`
+}
+
+// 読書目標レスポンス用構造体
+type GoalResponse struct {
+	ID        uint         `json:"id"`
+	Title     string       `json:"title"`
+	Metric    string       `json:"metric"`
+	Period    string       `json:"period"`
+	StartDate string       `json:"startDate"`
+	EndDate   string       `json:"endDate"`
+	Target    uint         `json:"target"`
+	Progress  GoalProgress `json:"progress"`
+	CreatedAt int64        `json:"createdAt"`
+}
+
+// レスポンス用読書目標の進捗構造体
+type GoalProgress struct {
+	Current                 int64   `json:"current"`                 // 期間内に読んだ書籍数、またはページ数
+	Percentage              float64 `json:"percentage"`              // 目標に対する達成率(%)
+	Expected                float64 `json:"expected"`                // 経過日数に応じて、今日までに見込まれる進捗
+	Difference              float64 `json:"difference"`              // 見込まれる進捗との差(正の場合は予定より進んでいる)
+	Status                  string  `json:"status"`                  // 進捗状況
+	ElapsedDays             int     `json:"elapsedDays"`             // 今日を含む経過日数
+	TotalDays               int     `json:"totalDays"`               // 期間の日数
+	ProjectedTotal          float64 `json:"projectedTotal"`          // 現在のペースで読み続けた場合の、期間終了時の見込み
+	ProjectedCompletionDate string  `json:"projectedCompletionDate"` // 現在のペースで読み続けた場合の達成見込み日(見込めない場合は空文字)
+	CompletedDate           string  `json:"completedDate"`           // 目標を達成した日(未達成の場合は空文字)
+}
@@ -0,0 +1,68 @@
+package entity
+
+// インポートジョブのステータス
+const (
+	ImportJobStatusPending = "pending"
+	ImportJobStatusRunning = "running"
+	ImportJobStatusDone    = "done"
+	ImportJobStatusFailed  = "failed"
+)
+
+// インポート行の処理結果
+const (
+	ImportRowActionCreated = "created"
+	ImportRowActionSkipped = "skipped"
+	ImportRowActionError   = "error"
+)
+
+// インポートジョブモデルエンティティ
+type ImportJob struct {
+	ID           uint   `gorm:"primaryKey"`
+	Source       string `gorm:"type:varchar;not null"`
+	Status       string `gorm:"type:varchar;not null"`
+	DryRun       bool
+	TotalRows    uint
+	CreatedRows  uint
+	SkippedRows  uint
+	FailedRows   uint
+	ErrorMessage string `gorm:"type:text"`
+	CreatedAt    int64  `gorm:"autoCreateTime"`
+	UpdatedAt    int64  `gorm:"autoUpdateTime"`
+	UserID       uint   `gorm:"index"`
+	User         User   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// インポートジョブの行単位の処理結果モデルエンティティ
+type ImportJobRow struct {
+	ID          uint      `gorm:"primaryKey"`
+	RowNumber   uint      `gorm:"not null"`
+	Title       string    `gorm:"type:varchar"`
+	Author      string    `gorm:"type:varchar"`
+	Action      string    `gorm:"type:varchar;not null"`
+	Message     string    `gorm:"type:text"`
+	ImportJobID uint      `gorm:"index"`
+	ImportJob   ImportJob `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// レスポンス用インポートジョブ構造体
+type ResponseImportJob struct {
+	ID           uint                   `json:"id"`
+	Source       string                 `json:"source"`
+	Status       string                 `json:"status"`
+	DryRun       bool                   `json:"dryRun"`
+	TotalRows    uint                   `json:"totalRows"`
+	CreatedRows  uint                   `json:"createdRows"`
+	SkippedRows  uint                   `json:"skippedRows"`
+	FailedRows   uint                   `json:"failedRows"`
+	ErrorMessage string                 `json:"errorMessage"`
+	Rows         []ResponseImportJobRow `json:"rows"`
+}
+
+// レスポンス用インポートジョブ行構造体
+type ResponseImportJobRow struct {
+	RowNumber uint   `json:"rowNumber"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Action    string `json:"action"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+// 通知の種類
+const (
+	NotificationTypePriceDrop = "priceDrop" // 監視している書籍の価格が目標価格以下になった
+)
+
+// 通知モデルエンティティ
+type Notification struct {
+	ID        uint   `gorm:"primaryKey"`
+	Type      string `gorm:"type:varchar;not null"`
+	Message   string `gorm:"type:text"`
+	Price     uint   // 価格の通知の場合の価格
+	IsRead    bool   `gorm:"not null;default:false"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	UpdatedAt int64  `gorm:"autoUpdateTime"`
+	UserID    uint   `gorm:"index"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	BookID    uint
+	Book      Book `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// レスポンス用通知構造体
+type ResponseNotification struct {
+	ID        uint   `json:"id"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	Price     uint   `json:"price"`
+	IsRead    bool   `json:"isRead"`
+	CreatedAt int64  `json:"createdAt"`
+	BookID    uint   `json:"bookId"`
+	BookTitle string `json:"bookTitle"`
+	BuyLink   string `json:"buyLink"` // 価格の通知の場合の購入リンク
+}
@@ -0,0 +1,54 @@
+package entity
+
+// Open Library Search APIのレスポンス用構造体
+type ResponseFromOpenLibrarySearch struct {
+	NumFound int                       `json:"numFound"`
+	Docs     []BookDataFromOpenLibrary `json:"docs"`
+}
+
+// Open Library Search APIのレスポンス用構造体(docs配下)
+type BookDataFromOpenLibrary struct {
+	Key                 string   `json:"key"`
+	Title               string   `json:"title"`
+	AuthorName          []string `json:"author_name"`
+	CoverI              int      `json:"cover_i"`
+	FirstPublishYear    int      `json:"first_publish_year"`
+	NumberOfPagesMedian uint     `json:"number_of_pages_median"`
+	ISBN                []string `json:"isbn"`
+	EditionKey          []string `json:"edition_key"`
+	Subject             []string `json:"subject"`
+	Publisher           []string `json:"publisher"`
+	Language            []string `json:"language"`
+}
+
+// Open Library Books APIのレスポンス用構造体(jscmd=data)
+type EditionDataFromOpenLibrary struct {
+	Key           string                     `json:"key"`
+	Title         string                     `json:"title"`
+	Authors       []NameFromOpenLibrary      `json:"authors"`
+	PublishDate   string                     `json:"publish_date"`
+	NumberOfPages uint                       `json:"number_of_pages"`
+	Cover         CoverFromOpenLibrary       `json:"cover"`
+	Identifiers   IdentifiersFromOpenLibrary `json:"identifiers"`
+	Subjects      []NameFromOpenLibrary      `json:"subjects"`
+	Publishers    []NameFromOpenLibrary      `json:"publishers"`
+}
+
+// Open Library Books APIのレスポンス用構造体(authors配下など)
+type NameFromOpenLibrary struct {
+	Name string `json:"name"`
+}
+
+// Open Library Books APIのレスポンス用構造体(cover配下)
+type CoverFromOpenLibrary struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+// Open Library Books APIのレスポンス用構造体(identifiers配下)
+type IdentifiersFromOpenLibrary struct {
+	ISBN10      []string `json:"isbn_10"`
+	ISBN13      []string `json:"isbn_13"`
+	OpenLibrary []string `json:"openlibrary"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+// 書籍の価格の監視モデルエンティティ
+// 販売価格が目標価格以下になった場合に、ユーザに通知する
+type PriceWatch struct {
+	ID                uint  `gorm:"primaryKey"`
+	TargetPrice       uint  `gorm:"not null"` // 通知する価格の上限
+	LastNotifiedPrice uint  // 最後に通知した価格
+	Notified          bool  `gorm:"not null;default:false"` // 目標価格以下となったことを通知済みか(目標価格を上回った場合はfalseに戻す)
+	CreatedAt         int64 `gorm:"autoCreateTime"`
+	UpdatedAt         int64 `gorm:"autoUpdateTime"`
+	UserID            uint  `gorm:"uniqueIndex:price_watch_user_and_book_unique_idx"`
+	User              User  `gorm:"constraint:OnDelete:CASCADE"`
+	BookID            uint  `gorm:"uniqueIndex:price_watch_user_and_book_unique_idx;index"`
+	Book              Book  `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 書籍の価格履歴モデルエンティティ
+// 価格、または販売状況が前回の取得時から変わった場合に記録する
+type PriceHistory struct {
+	ID        uint   `gorm:"primaryKey"`
+	IsForSale bool   `gorm:"not null"`
+	Price     uint   // 販売されていない場合は0
+	BuyLink   string `gorm:"type:varchar"`
+	Provider  string `gorm:"type:varchar"` // 価格を取得した書籍メタデータプロバイダ名
+	CheckedAt int64  `gorm:"not null"`     // 価格を取得した日時
+	BookID    uint   `gorm:"index"`
+	Book      Book   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 書籍の価格の監視登録リクエスト用構造体
+type WatchPriceRequest struct {
+	TargetPrice uint `json:"targetPrice" validate:"required"`
+}
+
+// 書籍の価格レスポンス用構造体
+type BookPricesResponse struct {
+	BookID  uint                `json:"bookId"`
+	Current *ResponsePrice      `json:"current"` // 価格を取得していない場合はnull
+	History []ResponsePrice     `json:"history"` // 新しい順
+	Watch   *ResponsePriceWatch `json:"watch"`   // 未ログイン、または監視していない場合はnull
+}
+
+// レスポンス用の書籍の価格構造体
+type ResponsePrice struct {
+	IsForSale bool   `json:"isForSale"`
+	Price     uint   `json:"price"`
+	BuyLink   string `json:"buyLink"`
+	CheckedAt int64  `json:"checkedAt"`
+}
+
+// レスポンス用の書籍の価格の監視構造体
+type ResponsePriceWatch struct {
+	BookID      uint  `json:"bookId"`
+	TargetPrice uint  `json:"targetPrice"`
+	CreatedAt   int64 `json:"createdAt"`
+}
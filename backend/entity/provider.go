@@ -0,0 +1,49 @@
+package entity
+
+// 書籍メタデータプロバイダから取得した書籍構造体
+type ProviderBook struct {
+	ID            string
+	Provider      string
+	Title         string
+	Authors       []string
+	ThumbnailLink string
+	PublishedDate string
+	NumOfPages    uint
+	ISBN10        string
+	ISBN13        string
+	Categories    []string // プロバイダでの分類(ジャンル、件名)
+	Description   string
+	Publisher     string
+	Language      string // ISO 639-1の言語コード
+	IsForSale     bool
+	Price         uint
+	BuyLink       string
+}
+
+// 書籍検索の並び順
+const (
+	SearchOrderRelevance = "relevance"
+	SearchOrderNewest    = "newest"
+)
+
+// 書籍メタデータプロバイダへの検索条件構造体
+type ProviderSearchQuery struct {
+	Query     string
+	Title     string // タイトルに含まれる語句
+	Author    string // 著者名に含まれる語句
+	ISBN      string
+	Publisher string // 出版社名に含まれる語句
+	Subject   string // 分野(カテゴリ)に含まれる語句
+	Language  string // ISO 639-1の言語コード(例: "ja")
+	Page      int    // 1始まりのページ番号(0の場合は1ページ目)
+	PageSize  int    // 1ページあたりの件数(0の場合はプロバイダの既定値)
+	EbookOnly bool   // trueの場合は電子書籍のみ
+	ForSale   bool   // trueの場合は販売中の書籍のみ
+	OrderBy   string // SearchOrderRelevance、またはSearchOrderNewest
+}
+
+// 書籍メタデータプロバイダからの検索結果構造体
+type ProviderSearchResult struct {
+	Books      []ProviderBook
+	TotalItems int
+}
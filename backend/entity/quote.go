@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// 引用(ハイライト)モデルエンティティ
+type Quote struct {
+	ID            uint   `gorm:"primaryKey"`
+	Text          string `gorm:"type:text;not null"`
+	Page          uint
+	Location      string    `gorm:"type:varchar"`
+	Note          string    `gorm:"type:text"`
+	Tags          string    `gorm:"type:varchar"`
+	HighlightedAt time.Time `gorm:"type:timestamp"`
+	CreatedAt     int64     `gorm:"autoCreateTime"`
+	UpdatedAt     int64     `gorm:"autoUpdateTime"`
+	ReviewID      uint      `gorm:"index"`
+	Review        Review    `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// 引用登録リクエスト用構造体
+type CreateQuoteRequest struct {
+	Text     string `json:"text" validate:"required"`
+	Page     uint   `json:"page"`
+	Location string `json:"location"`
+	Note     string `json:"note"`
+	Tags     string `json:"tags"`
+}
+
+// 引用更新リクエスト用構造体
+type UpdateQuoteRequest struct {
+	Text     string `json:"text" validate:"required"`
+	Page     uint   `json:"page"`
+	Location string `json:"location"`
+	Note     string `json:"note"`
+	Tags     string `json:"tags"`
+}
+
+// 引用検索レスポンス用構造体
+type GetQuotesResponse struct {
+	ResponseQuotes []ResponseQuote `json:"items"`
+	TotalPages     int64           `json:"totalPages"`
+}
+
+// レスポンス用引用構造体
+type ResponseQuote struct {
+	ID            uint   `json:"id"`
+	Text          string `json:"text"`
+	Page          uint   `json:"page"`
+	Location      string `json:"location"`
+	Note          string `json:"note"`
+	Tags          string `json:"tags"`
+	HighlightedAt string `json:"highlightedAt"`
+	ReviewID      uint   `json:"reviewId"`
+	BookTitle     string `json:"bookTitle"`
+	BookAuthor    string `json:"bookAuthor"`
+}
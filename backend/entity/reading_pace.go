@@ -0,0 +1,33 @@
+package entity
+
+// 読書ペースの算出元
+const (
+	ReadingPaceSourceBook    = "book"    // その書籍の読書記録から算出
+	ReadingPaceSourceOverall = "overall" // その書籍の読書記録が無いため、全ての書籍の読書記録から算出
+)
+
+// 読書中の書籍一覧レスポンス用構造体
+type CurrentlyReadingResponse struct {
+	PaceDays    int                    `json:"paceDays"`    // 読書ペースの算出に用いた期間の日数(今日まで)
+	PagesPerDay float64                `json:"pagesPerDay"` // 期間内の、全ての書籍での1日あたりの読んだページ数
+	Books       []CurrentlyReadingBook `json:"books"`       // 読了に近い順
+}
+
+// レスポンス用読書中の書籍構造体
+type CurrentlyReadingBook struct {
+	ReviewID            uint    `json:"reviewId"`
+	BookID              uint    `json:"bookId"`
+	BookTitle           string  `json:"bookTitle"`
+	BookAuthor          string  `json:"bookAuthor"`
+	BookThumbnailLink   string  `json:"bookThumbnailLink"`
+	ReadPages           uint    `json:"readPages"`
+	NumOfPages          uint    `json:"numOfPages"`          // 書籍のページ数(不明な場合は0)
+	RemainingPages      uint    `json:"remainingPages"`      // 残りのページ数(ページ数が不明な場合は0)
+	Percentage          float64 `json:"percentage"`          // 読んだページ数の割合(%)
+	PagesPerDay         float64 `json:"pagesPerDay"`         // 1日あたりの読んだページ数
+	PaceSource          string  `json:"paceSource"`          // 読書ペースの算出元(算出できない場合は空文字)
+	EstimatedDaysLeft   int     `json:"estimatedDaysLeft"`   // 現在のペースで読み続けた場合の、読了までの日数(見込めない場合は-1)
+	EstimatedFinishDate string  `json:"estimatedFinishDate"` // 現在のペースで読み続けた場合の読了見込み日(見込めない場合は空文字)
+	StartReadAt         string  `json:"startReadAt"`
+	LastReadAt          string  `json:"lastReadAt"` // 最後に読んだページ数を記録した日(記録が無い場合は空文字)
+}
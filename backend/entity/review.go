@@ -2,6 +2,28 @@ package entity
 
 import "time"
 
+// 読書ステータス
+const (
+	ReadingStatusToRead  = "ToRead"
+	ReadingStatusReading = "Reading"
+	ReadingStatusFinish  = "Finish"
+)
+
+// レビューの公開範囲
+const (
+	ReviewVisibilityPublic  = "public"
+	ReviewVisibilityPrivate = "private"
+)
+
+// 一括操作の種類
+const (
+	BulkOperationSetStatus     = "setStatus"
+	BulkOperationAddTags       = "addTags"
+	BulkOperationRemoveTags    = "removeTags"
+	BulkOperationSetVisibility = "setVisibility"
+	BulkOperationDelete        = "delete"
+)
+
 // レビューモデルエンティティ
 type Review struct {
 	ID            uint   `gorm:"primaryKey"`
@@ -10,30 +32,42 @@ type Review struct {
 	ReadingStatus string `gorm:"type:varchar"`
 	ReadPages     uint
 	StartReadAt   time.Time `gorm:"type:timestamp"`
-	FinishReadAt  time.Time `gorm:"type:timestamp"`
+	FinishReadAt  time.Time `gorm:"type:timestamp;index:review_user_and_finish_read_at_idx,priority:2"`
 	Tags          string    `gorm:"type:varchar"`
+	Visibility    string    `gorm:"type:varchar;not null;default:private"`
 	CreatedAt     int64     `gorm:"autoCreateTime"`
 	UpdatedAt     int64     `gorm:"autoUpdateTime"`
-	UserID        uint
-	User          User `gorm:"constraint:OnDelete:CASCADE"`
+	UserID        uint      `gorm:"index:review_user_and_finish_read_at_idx,priority:1"`
+	User          User      `gorm:"constraint:OnDelete:CASCADE"`
 	BookID        uint
 	Book          Book `gorm:"constraint:OnDelete:CASCADE"`
 }
 
 // レビュー登録リクエスト用構造体
 type CreateReviewRequest struct {
-	Comment           string  `json:"comment" validate:"required"`
-	Rating            float64 `json:"rating" validate:"required,gte=0.5,lte=5.0"`
-	ReadingStatus     string  `json:"readingStatus" validate:"required"`
-	ReadPages         uint    `json:"readPages"`
-	StartReadAt       string  `json:"startReadAt"`
-	FinishReadAt      string  `json:"finishReadAt"`
-	Tags              string  `json:"tags"`
-	BookTitle         string  `json:"bookTitle" validate:"required"`
-	BookAuthor        string  `json:"bookAuthor" validate:"required"`
-	BookThumbnailLink string  `json:"bookThumbnailLink"`
-	BookPublishedDate string  `json:"bookPublishedDate"`
-	BookNumOfPages    uint    `json:"bookNumOfPages"`
+	Comment           string               `json:"comment" validate:"required"`
+	Rating            float64              `json:"rating" validate:"required,gte=0.5,lte=5.0"`
+	ReadingStatus     string               `json:"readingStatus" validate:"required"`
+	ReadPages         uint                 `json:"readPages"`
+	StartReadAt       string               `json:"startReadAt"`
+	FinishReadAt      string               `json:"finishReadAt"`
+	Tags              string               `json:"tags"`
+	Visibility        string               `json:"visibility" validate:"omitempty,oneof=public private"`
+	BookTitle         string               `json:"bookTitle" validate:"required"`
+	BookAuthor        string               `json:"bookAuthor" validate:"required"`
+	BookThumbnailLink string               `json:"bookThumbnailLink"`
+	BookPublishedDate string               `json:"bookPublishedDate"`
+	BookNumOfPages    uint                 `json:"bookNumOfPages"`
+	BookISBN10        string               `json:"bookIsbn10"`
+	BookISBN13        string               `json:"bookIsbn13"`
+	BookProvider      string               `json:"bookProvider"`
+	BookVolumeID      string               `json:"bookVolumeId"`
+	BookWorkID        uint                 `json:"bookWorkId"` // 登録済みの作品の版として登録する場合に指定する
+	BookCategories    []string             `json:"bookCategories"`
+	BookDescription   string               `json:"bookDescription"`
+	BookPublisher     string               `json:"bookPublisher"`
+	BookLanguage      string               `json:"bookLanguage"`
+	BookContributors  []ContributorRequest `json:"bookContributors" validate:"dive"` // 未指定の場合はbookAuthorから登録する
 }
 
 // レビュー更新リクエスト用構造体
@@ -45,6 +79,34 @@ type UpdateReviewRequest struct {
 	StartReadAt   string  `json:"startReadAt"`
 	FinishReadAt  string  `json:"finishReadAt"`
 	Tags          string  `json:"tags"`
+	Visibility    string  `json:"visibility" validate:"omitempty,oneof=public private"`
+}
+
+// レビュー一括操作リクエスト用構造体
+type BulkReviewRequest struct {
+	ReviewIDs  []uint                `json:"reviewIds" validate:"required,min=1,max=500"`
+	Operations []BulkReviewOperation `json:"operations" validate:"required,min=1,dive"`
+}
+
+// レビュー一括操作リクエスト用構造体(operations配下)
+type BulkReviewOperation struct {
+	Type          string   `json:"type" validate:"required,oneof=setStatus addTags removeTags setVisibility delete"`
+	ReadingStatus string   `json:"readingStatus" validate:"required_if=Type setStatus"`
+	Tags          []string `json:"tags" validate:"required_if=Type addTags,required_if=Type removeTags"`
+	Visibility    string   `json:"visibility" validate:"required_if=Type setVisibility"`
+}
+
+// レビュー一括操作レスポンス用構造体
+type BulkReviewResponse struct {
+	Applied bool               `json:"applied"`
+	Results []BulkReviewResult `json:"results"`
+}
+
+// レビュー一括操作のレビューごとの処理結果
+type BulkReviewResult struct {
+	ReviewID uint   `json:"reviewId"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
 }
 
 // 書籍検索レスポンス用構造体
@@ -55,25 +117,28 @@ type GetReviewsResponse struct {
 
 // レスポンス用レビュー構造体
 type ResponseReview struct {
-	ID                uint    `json:"id"`
-	Comment           string  `json:"comment"`
-	Rating            float64 `json:"rating"`
-	ReadingStatus     string  `json:"readingStatus"`
-	ReadPages         uint    `json:"readPages"`
-	StartReadAt       string  `json:"startReadAt"`
-	FinishReadAt      string  `json:"finishReadAt"`
-	Tags              string  `json:"tags"`
-	BookTitle         string  `json:"bookTitle"`
-	BookAuthor        string  `json:"bookAuthor"`
-	BookThumbnailLink string  `json:"bookThumbnailLink"`
-	BookPublishedDate string  `json:"bookPublishedDate"`
-	BookNumOfPages    uint    `json:"bookNumOfPages"`
+	ID                uint            `json:"id"`
+	Comment           string          `json:"comment"`
+	Rating            float64         `json:"rating"`
+	ReadingStatus     string          `json:"readingStatus"`
+	ReadPages         uint            `json:"readPages"`
+	StartReadAt       string          `json:"startReadAt"`
+	FinishReadAt      string          `json:"finishReadAt"`
+	Tags              string          `json:"tags"`
+	Visibility        string          `json:"visibility"`
+	BookTitle         string          `json:"bookTitle"`
+	BookAuthor        string          `json:"bookAuthor"`
+	BookThumbnailLink string          `json:"bookThumbnailLink"`
+	BookPublishedDate string          `json:"bookPublishedDate"`
+	BookNumOfPages    uint            `json:"bookNumOfPages"`
+	Quotes            []ResponseQuote `json:"quotes,omitempty" gorm:"-"`
 }
 
 // レビュー統計情報レスポンス用構造体
 type GetReviewStatsResponse struct {
-	NumOfReadBooksOfMonth int64 `json:"numOfReadBooksOfMonth"`
-	NumOfReadPagesOfMonth int64 `json:"numOfReadPagesOfMonth"`
-	NumOfReadBooksOfYear  int64 `json:"numOfReadBooksOfYear"`
-	NumOfReadPagesOfYear  int64 `json:"numOfReadPagesOfYear"`
+	NumOfReadBooksOfMonth int64        `json:"numOfReadBooksOfMonth"`
+	NumOfReadPagesOfMonth int64        `json:"numOfReadPagesOfMonth"`
+	NumOfReadBooksOfYear  int64        `json:"numOfReadBooksOfYear"`
+	NumOfReadPagesOfYear  int64        `json:"numOfReadPagesOfYear"`
+	GenresOfYear          []GenreCount `json:"genresOfYear"` // 対象年に読んだ書籍のジャンル別の冊数
 }
@@ -0,0 +1,15 @@
+package entity
+
+// 月別の読書の統計モデルエンティティ
+// レビューの読了日の月ごとに、読了した書籍数とページ数を集計したもの(レビューの登録、更新、削除時に更新する)
+type MonthlyReviewStat struct {
+	ID             uint  `gorm:"primaryKey"`
+	Year           int   `gorm:"uniqueIndex:monthly_review_stat_user_and_month_unique_idx,priority:2"`
+	Month          int   `gorm:"uniqueIndex:monthly_review_stat_user_and_month_unique_idx,priority:3"`
+	NumOfReadBooks int64 `gorm:"not null;default:0"`
+	NumOfReadPages int64 `gorm:"not null;default:0"`
+	CreatedAt      int64 `gorm:"autoCreateTime"`
+	UpdatedAt      int64 `gorm:"autoUpdateTime"`
+	UserID         uint  `gorm:"uniqueIndex:monthly_review_stat_user_and_month_unique_idx,priority:1"`
+	User           User  `gorm:"constraint:OnDelete:CASCADE"`
+}
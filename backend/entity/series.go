@@ -0,0 +1,64 @@
+package entity
+
+// シリーズモデルエンティティ
+type Series struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar;not null"`
+	Description string `gorm:"type:text"`
+	CreatedAt   int64  `gorm:"autoCreateTime"`
+	UpdatedAt   int64  `gorm:"autoUpdateTime"`
+	UserID      uint   // シリーズを登録したユーザ(巻の追加、削除を行える)
+	User        User   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// シリーズの巻モデルエンティティ
+// 巻は作品単位で登録し、いずれの版を読んでも既読とみなす
+type SeriesEntry struct {
+	ID        uint    `gorm:"primaryKey"`
+	Position  float64 `gorm:"not null"` // シリーズ内での巻数(外伝などは1.5のような小数で表す)
+	CreatedAt int64   `gorm:"autoCreateTime"`
+	UpdatedAt int64   `gorm:"autoUpdateTime"`
+	SeriesID  uint    `gorm:"uniqueIndex:series_and_work_unique_idx"`
+	Series    Series  `gorm:"constraint:OnDelete:CASCADE"`
+	WorkID    uint    `gorm:"uniqueIndex:series_and_work_unique_idx"`
+	Work      Work    `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// シリーズ登録リクエスト用構造体
+type CreateSeriesRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// シリーズの巻登録リクエスト用構造体
+type AddSeriesEntryRequest struct {
+	BookID   uint    `json:"bookId" validate:"required"`
+	Position float64 `json:"position" validate:"gte=0"`
+}
+
+// シリーズ詳細レスポンス用構造体
+type SeriesResponse struct {
+	ID          uint                  `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Entries     []ResponseSeriesEntry `json:"entries"`
+}
+
+// レスポンス用シリーズの巻構造体
+type ResponseSeriesEntry struct {
+	ID            uint    `json:"id"`
+	Position      float64 `json:"position"`
+	WorkID        uint    `json:"workId"`
+	BookID        uint    `json:"bookId"` // 作品の代表的な版(最初に登録された版)
+	Title         string  `json:"title"`
+	Author        string  `json:"author"`
+	ThumbnailLink string  `json:"thumbnailLink"`
+	ReadingStatus string  `json:"readingStatus"` // ログインユーザの読書ステータス(未レビューの場合は空文字)
+}
+
+// シリーズの次に読む巻レスポンス用構造体
+type NextInSeries struct {
+	SeriesID   uint                `json:"seriesId"`
+	SeriesName string              `json:"seriesName"`
+	Entry      ResponseSeriesEntry `json:"entry"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+// 詳細な読書統計情報レスポンス用構造体
+// 期間内に読了したレビューを対象とする
+type DetailedStatsResponse struct {
+	From                string           `json:"from"` // 対象期間の最初の月("2006-01"形式)
+	To                  string           `json:"to"`   // 対象期間の最後の月("2006-01"形式)
+	NumOfReadBooks      int64            `json:"numOfReadBooks"`
+	NumOfReadPages      int64            `json:"numOfReadPages"`
+	AverageRating       float64          `json:"averageRating"`       // 評価の無いレビューは除く
+	AverageDaysToFinish float64          `json:"averageDaysToFinish"` // 読み始めた日から読了日までの日数(両日を含む)の平均
+	Monthly             []MonthlyStats   `json:"monthly"`             // 月別の推移(読了していない月を含む)
+	RatingDistribution  []RatingCount    `json:"ratingDistribution"`  // 評価別の冊数(0.5刻み)
+	Tags                []StatsBreakdown `json:"tags"`                // タグ別(冊数の多い順)
+	Authors             []StatsBreakdown `json:"authors"`             // 著者別(冊数の多い順)
+	Genres              []StatsBreakdown `json:"genres"`              // ジャンル別(冊数の多い順)
+	PageCounts          []StatsBreakdown `json:"pageCounts"`          // ページ数の範囲別(範囲の順)
+	MostReadAuthors     []StatsBreakdown `json:"mostReadAuthors"`     // 最も多く読んだ著者(上位5人)
+	LongestBook         *StatsBook       `json:"longestBook"`         // ページ数の不明な書籍は除く(該当しない場合はnull)
+	ShortestBook        *StatsBook       `json:"shortestBook"`        // ページ数の不明な書籍は除く(該当しない場合はnull)
+}
+
+// レスポンス用月別統計情報構造体
+type MonthlyStats struct {
+	Month          string  `json:"month"` // "2006-01"形式
+	NumOfReadBooks int64   `json:"numOfReadBooks"`
+	NumOfReadPages int64   `json:"numOfReadPages"`
+	AverageRating  float64 `json:"averageRating"` // 評価したレビューが無い月は0
+}
+
+// レスポンス用の項目別統計情報構造体
+type StatsBreakdown struct {
+	Key            string `json:"key"`  // タグ名、著者ID、ジャンルのスラッグ、またはページ数の範囲
+	Name           string `json:"name"` // 表示名
+	NumOfReadBooks int64  `json:"numOfReadBooks"`
+	NumOfReadPages int64  `json:"numOfReadPages"`
+}
+
+// レスポンス用の統計情報の書籍構造体
+type StatsBook struct {
+	ReviewID     uint    `json:"reviewId"`
+	BookID       uint    `json:"bookId"`
+	Title        string  `json:"title"`
+	Author       string  `json:"author"`
+	NumOfPages   uint    `json:"numOfPages"`
+	Rating       float64 `json:"rating"`
+	FinishReadAt string  `json:"finishReadAt"`
+}
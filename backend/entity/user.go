@@ -2,12 +2,14 @@ package entity
 
 // Userモデルエンティティ
 type User struct {
-	ID        uint   `gorm:"primaryKey"`
-	Name      string `gorm:"type:varchar(255);not null"`
-	Email     string `gorm:"type:varchar(255);unique;not null"`
-	Password  string `gorm:"type:varchar;not null"`
-	CreatedAt int64  `gorm:"autoCreateTime"`
-	UpdatedAt int64  `gorm:"autoUpdateTime"`
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar(255);not null"`
+	Email       string `gorm:"type:varchar(255);unique;not null"`
+	Password    string `gorm:"type:varchar;not null"`
+	IsModerator bool   `gorm:"not null;default:false"`                   // 書籍データの修正提案を承認、却下できる
+	TimeZone    string `gorm:"type:varchar;not null;default:Asia/Tokyo"` // 日付の集計に用いるタイムゾーン(例: "Asia/Tokyo")
+	CreatedAt   int64  `gorm:"autoCreateTime"`
+	UpdatedAt   int64  `gorm:"autoUpdateTime"`
 }
 
 // ユーザ登録リクエスト用構造体
@@ -16,6 +18,7 @@ type RegisterRequest struct {
 	Email                string `json:"email" validate:"required"`
 	Password             string `json:"password" validate:"required"`
 	PasswordConfirmation string `json:"passwordConfirmation" validate:"required"`
+	TimeZone             string `json:"timeZone"` // 未指定の場合は"Asia/Tokyo"
 }
 
 // ログインリクエスト用構造体
@@ -30,11 +33,13 @@ type UpdateAccountRequest struct {
 	NewName     string `json:"newName" validate:"required"`
 	NewEmail    string `json:"newEmail" validate:"required"`
 	NewPassword string `json:"newPassword" validate:"required"`
+	NewTimeZone string `json:"newTimeZone"` // 未指定の場合は変更しない
 }
 
 // レスポンス用ユーザ構造体
 type ResponseUser struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	TimeZone string `json:"timeZone"`
+	Token    string `json:"token"`
 }
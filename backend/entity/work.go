@@ -0,0 +1,34 @@
+package entity
+
+// 作品モデルエンティティ
+// 翻訳版、単行本、文庫本、電子書籍など、同じ作品の版(Book)をまとめる
+type Work struct {
+	ID        uint   `gorm:"primaryKey"`
+	Title     string `gorm:"type:varchar;not null"`
+	Author    string `gorm:"type:varchar;not null"`
+	MatchKey  string `gorm:"type:varchar;index"` // 版の照合用キー(空白を除き小文字にしたタイトルと著者)
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	UpdatedAt int64  `gorm:"autoUpdateTime"`
+}
+
+// 作品詳細レスポンス用構造体
+type WorkResponse struct {
+	ID        uint               `json:"id"`
+	Title     string             `json:"title"`
+	Author    string             `json:"author"`
+	Editions  []WorkEdition      `json:"editions"`
+	Community BookCommunityStats `json:"community"` // 全ての版の公開レビューの集計
+}
+
+// 作品詳細レスポンス用の版構造体
+type WorkEdition struct {
+	ID            uint   `json:"id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	ThumbnailLink string `json:"thumbnailLink"`
+	PublishedDate string `json:"publishedDate"`
+	NumOfPages    uint   `json:"numOfPages"`
+	ISBN10        string `json:"isbn10"`
+	ISBN13        string `json:"isbn13"`
+	NumOfReviews  int64  `json:"numOfReviews"` // 全ユーザの公開レビューの件数
+}
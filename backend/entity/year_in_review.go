@@ -0,0 +1,25 @@
+package entity
+
+// 年間の読書のまとめ構造体
+type YearInReview struct {
+	Year            int              `json:"year"`
+	UserName        string           `json:"userName"`
+	NumOfReadBooks  int64            `json:"numOfReadBooks"`
+	NumOfReadPages  int64            `json:"numOfReadPages"`
+	AverageRating   float64          `json:"averageRating"`
+	TopRatedBooks   []StatsBook      `json:"topRatedBooks"`   // 評価の高い書籍(上位5冊)
+	LongestBook     *StatsBook       `json:"longestBook"`     // 該当しない場合はnull
+	FavoriteTags    []StatsBreakdown `json:"favoriteTags"`    // 最も多く付けたタグ(上位5件)
+	FavoriteAuthors []StatsBreakdown `json:"favoriteAuthors"` // 最も多く読んだ著者(上位5人)
+	Monthly         []MonthlyStats   `json:"monthly"`         // 1月から12月までの月別の推移
+	FirstBook       *StatsBook       `json:"firstBook"`       // その年に最初に読了した書籍(該当しない場合はnull)
+	LastBook        *StatsBook       `json:"lastBook"`        // その年に最後に読了した書籍(該当しない場合はnull)
+}
+
+// 年間の読書のまとめレスポンス用構造体
+// JSON以外の形式の場合は、出力したデータを返す
+type YearInReviewReport struct {
+	Summary     YearInReview
+	Data        []byte
+	ContentType string
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"fmt"
+	"os"
+	// ユーザごとのタイムゾーンを、タイムゾーンデータの無い環境でも読み込めるようにする
+	_ "time/tzdata"
 
 	"github.com/KoyoMiyazaki/Book-Reviewer/db"
 	"github.com/KoyoMiyazaki/Book-Reviewer/router"
+	"github.com/KoyoMiyazaki/Book-Reviewer/service"
 )
 
 func main() {
@@ -17,6 +21,42 @@ func main() {
 		}
 	}()
 
+	// go run . rebuild-stats の場合は、月別の読書の統計をレビューから集計し直して終了する
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-stats" {
+		if err := service.RebuildMonthlyReviewStats(); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	// 著者が登録されていない書籍について、著者を登録する
+	if err := service.MigrateBookAuthors(); err != nil {
+		fmt.Println(err)
+	}
+	// ジャンルを登録し、ジャンルが登録されていない書籍について、ジャンルを登録する
+	if err := service.MigrateGenres(); err != nil {
+		fmt.Println(err)
+	}
+	// 作品が登録されていない書籍について、作品を登録する
+	if err := service.MigrateBookWorks(); err != nil {
+		fmt.Println(err)
+	}
+
+	// 月別の読書の統計が未作成の場合は、レビューから作成する
+	if err := service.MigrateMonthlyReviewStats(); err != nil {
+		fmt.Println(err)
+	}
+
+	// 環境変数に指定されたユーザを、モデレータにする
+	if err := service.MigrateModerators(); err != nil {
+		fmt.Println(err)
+	}
+
+	// 書籍の重複検出を定期的に実行する
+	service.StartDuplicateDetection()
+	// 監視されている書籍の価格を定期的に取得する
+	service.StartPriceTracking()
+
 	if err := router.Init(); err != nil {
 		fmt.Println(err)
 	}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// キャッシュの保存先(Postgresのテーブルなど)のインターフェース
+type CacheStore interface {
+	// キーに対応する有効期限内の値を取得する(見つからない場合はfoundがfalse)
+	Get(key string, now time.Time) (value []byte, found bool, err error)
+	// キーに対応する値を保存する
+	Set(key string, value []byte, expiresAt time.Time) error
+}
+
+type bypassCacheKey struct{}
+
+// キャッシュを用いずにプロバイダへ問い合わせるコンテキストを返す(取得結果はキャッシュに保存する)
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// コンテキストでキャッシュの利用が無効化されているか判定する
+func isCacheBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypassed
+}
+
+// プロバイダへの問い合わせ結果をキャッシュするプロバイダ
+// メモリ上のLRUキャッシュを優先し、Storeが設定されている場合はその内容も参照する
+type Cache struct {
+	Provider BookProvider
+	TTL      time.Duration
+	Store    CacheStore
+
+	memory    *lruCache
+	hits      int64
+	storeHits int64
+	misses    int64
+	bypassed  int64
+}
+
+// Cacheを生成する(storeはnilでもよい)
+func NewCache(p BookProvider, size int, ttl time.Duration, store CacheStore) *Cache {
+	return &Cache{
+		Provider: p,
+		TTL:      ttl,
+		Store:    store,
+		memory:   newLRUCache(size),
+	}
+}
+
+func (p *Cache) Name() string {
+	return p.Provider.Name()
+}
+
+func (p *Cache) Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error) {
+	// 検索ワードは、大文字小文字と空白の違いを無視してキーにする
+	normalized := query
+	normalized.Query = normalizeQuery(query.Query)
+	normalized.Title = normalizeQuery(query.Title)
+	normalized.Author = normalizeQuery(query.Author)
+	normalized.ISBN = normalizeQuery(query.ISBN)
+	normalized.Publisher = normalizeQuery(query.Publisher)
+	normalized.Subject = normalizeQuery(query.Subject)
+	normalized.Language = normalizeQuery(query.Language)
+
+	var result entity.ProviderSearchResult
+	err := p.fetch(ctx, "search", normalized, &result, func() (any, error) {
+		return p.Provider.Search(ctx, query)
+	})
+	return result, err
+}
+
+func (p *Cache) LookupByID(ctx context.Context, id string) (entity.ProviderBook, error) {
+	var book entity.ProviderBook
+	err := p.fetch(ctx, "id", id, &book, func() (any, error) {
+		return p.Provider.LookupByID(ctx, id)
+	})
+	return book, err
+}
+
+func (p *Cache) LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error) {
+	var book entity.ProviderBook
+	err := p.fetch(ctx, "isbn", isbn, &book, func() (any, error) {
+		return p.Provider.LookupByISBN(ctx, isbn)
+	})
+	return book, err
+}
+
+// キャッシュの統計情報を返す
+func (p *Cache) Stats() entity.BookCacheStats {
+	stats := entity.BookCacheStats{
+		Hits:      atomic.LoadInt64(&p.hits),
+		StoreHits: atomic.LoadInt64(&p.storeHits),
+		Misses:    atomic.LoadInt64(&p.misses),
+		Bypassed:  atomic.LoadInt64(&p.bypassed),
+		Entries:   p.memory.len(),
+	}
+	if total := stats.Hits + stats.StoreHits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits+stats.StoreHits) / float64(total)
+	}
+	return stats
+}
+
+// キャッシュから値を取得し、無い場合はfetchで取得してキャッシュに保存する
+func (p *Cache) fetch(ctx context.Context, kind string, params any, dest any, fetch func() (any, error)) error {
+	key, err := cacheKey(p.Provider.Name(), kind, params)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	if isCacheBypassed(ctx) {
+		atomic.AddInt64(&p.bypassed, 1)
+	} else {
+		if value, found := p.memory.get(key, now); found {
+			atomic.AddInt64(&p.hits, 1)
+			return json.Unmarshal(value, dest)
+		}
+		if p.Store != nil {
+			value, found, err := p.Store.Get(key, now)
+			if err != nil {
+				log.Println(err)
+			}
+			if found {
+				atomic.AddInt64(&p.storeHits, 1)
+				p.memory.set(key, value, now.Add(p.TTL))
+				return json.Unmarshal(value, dest)
+			}
+		}
+		atomic.AddInt64(&p.misses, 1)
+	}
+
+	// エラーはキャッシュしない
+	result, err := fetch()
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := now.Add(p.TTL)
+	p.memory.set(key, value, expiresAt)
+	if p.Store != nil {
+		// 保存先への書き込みに失敗しても、取得結果は返す
+		if err := p.Store.Set(key, value, expiresAt); err != nil {
+			log.Println(err)
+		}
+	}
+	return json.Unmarshal(value, dest)
+}
+
+// プロバイダ名、問い合わせの種類、パラメータからキャッシュのキーを生成する
+func cacheKey(providerName, kind string, params any) (string, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(providerName + "\x00" + kind + "\x00" + string(encoded)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// 検索ワードを小文字にし、連続する空白を1つにまとめる
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// 有効期限付きのLRUキャッシュ
+type lruCache struct {
+	mutex    sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // 先頭ほど最近参照された要素
+}
+
+// LRUキャッシュの要素
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) get(key string, now time.Time) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *lruCache) set(key string, value []byte, expiresAt time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+	if element, ok := c.items[key]; ok {
+		element.Value = &lruEntry{key: key, value: value, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	// 容量を超えた場合は、最も長く参照されていない要素を削除
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"errors"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Postgresのテーブル(book_metadata_caches)をキャッシュの保存先とするCacheStore
+type DBCacheStore struct {
+	DB *gorm.DB
+}
+
+func (s DBCacheStore) Get(key string, now time.Time) ([]byte, bool, error) {
+	var cache entity.BookMetadataCache
+	if err := s.DB.Where("key = ? AND expires_at > ?", key, now.Unix()).First(&cache).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return cache.Value, true, nil
+}
+
+func (s DBCacheStore) Set(key string, value []byte, expiresAt time.Time) error {
+	cache := entity.BookMetadataCache{Key: key, Value: value, ExpiresAt: expiresAt.Unix()}
+	// キーが既に存在する場合は値と有効期限を更新
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at"}),
+	}).Create(&cache).Error
+}
+
+// 有効期限切れのキャッシュを削除する
+func (s DBCacheStore) DeleteExpired(now time.Time) error {
+	return s.DB.Where("expires_at <= ?", now.Unix()).Delete(&entity.BookMetadataCache{}).Error
+}
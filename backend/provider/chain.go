@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// 複数のプロバイダに優先順に問い合わせるプロバイダ
+// 問い合わせに失敗したプロバイダは読み飛ばし、次のプロバイダにフォールバックする
+type Chain struct {
+	Providers []BookProvider
+	Merge     bool // trueの場合は、成功した全プロバイダの検索結果をマージする
+}
+
+// Chainを生成する
+func NewChain(merge bool, providers ...BookProvider) *Chain {
+	return &Chain{Providers: providers, Merge: merge}
+}
+
+func (p *Chain) Name() string {
+	names := make([]string, 0, len(p.Providers))
+	for _, provider := range p.Providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (p *Chain) Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error) {
+	var results []entity.ProviderSearchResult
+	var errs []error
+	for _, provider := range p.Providers {
+		result, err := provider.Search(ctx, query)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if !p.Merge {
+			return result, nil
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		return entity.ProviderSearchResult{}, chainError(errs)
+	}
+
+	// 検索結果をマージ(総件数は、最も多いプロバイダの件数とする)
+	merged := entity.ProviderSearchResult{}
+	var bookLists [][]entity.ProviderBook
+	for _, result := range results {
+		bookLists = append(bookLists, result.Books)
+		if result.TotalItems > merged.TotalItems {
+			merged.TotalItems = result.TotalItems
+		}
+	}
+	merged.Books = MergeBooks(bookLists...)
+
+	return merged, nil
+}
+
+func (p *Chain) LookupByID(ctx context.Context, id string) (entity.ProviderBook, error) {
+	return p.lookup(func(provider BookProvider) (entity.ProviderBook, error) {
+		return provider.LookupByID(ctx, id)
+	})
+}
+
+func (p *Chain) LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error) {
+	return p.lookup(func(provider BookProvider) (entity.ProviderBook, error) {
+		return provider.LookupByISBN(ctx, isbn)
+	})
+}
+
+// 見つかるまで、優先順にプロバイダに問い合わせる
+func (p *Chain) lookup(lookup func(BookProvider) (entity.ProviderBook, error)) (entity.ProviderBook, error) {
+	var errs []error
+	for _, provider := range p.Providers {
+		book, err := lookup(provider)
+		if err == nil {
+			return book, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		}
+	}
+
+	// 全てのプロバイダで見つからなかった場合はErrNotFoundを返す
+	if len(errs) == 0 {
+		return entity.ProviderBook{}, ErrNotFound
+	}
+	return entity.ProviderBook{}, chainError(errs)
+}
+
+// 全プロバイダのエラーを1つのエラーにまとめる
+func chainError(errs []error) error {
+	if len(errs) == 0 {
+		return errors.New("no book provider is configured")
+	}
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf("all book providers failed: %s", strings.Join(messages, "; "))
+}
+
+// 複数プロバイダの検索結果をマージする
+// ISBN、またはタイトルと著者が一致する書籍は1件にまとめ、先のプロバイダに無い項目を後のプロバイダの値で補う
+func MergeBooks(bookLists ...[]entity.ProviderBook) []entity.ProviderBook {
+	var merged []entity.ProviderBook
+	indexByKey := map[string]int{}
+	for _, books := range bookLists {
+		for _, book := range books {
+			keys := mergeKeys(book)
+
+			index, found := -1, false
+			for _, key := range keys {
+				if index, found = indexByKey[key]; found {
+					break
+				}
+			}
+			if !found {
+				index = len(merged)
+				merged = append(merged, book)
+			} else {
+				merged[index] = fillMissing(merged[index], book)
+			}
+
+			for _, key := range mergeKeys(merged[index]) {
+				indexByKey[key] = index
+			}
+		}
+	}
+	return merged
+}
+
+// 書籍の同一性の判定に用いるキーを返す
+func mergeKeys(book entity.ProviderBook) []string {
+	var keys []string
+	if book.ISBN13 != "" {
+		keys = append(keys, "isbn13:"+book.ISBN13)
+	}
+	if book.ISBN10 != "" {
+		keys = append(keys, "isbn10:"+book.ISBN10)
+	}
+	keys = append(keys, "title:"+strings.ToLower(strings.TrimSpace(book.Title))+"\x00"+strings.ToLower(strings.Join(book.Authors, ",")))
+	return keys
+}
+
+// baseの空の項目を、otherの値で補う
+func fillMissing(base, other entity.ProviderBook) entity.ProviderBook {
+	if len(base.Authors) == 0 {
+		base.Authors = other.Authors
+	}
+	if base.ThumbnailLink == "" {
+		base.ThumbnailLink = other.ThumbnailLink
+	}
+	if base.PublishedDate == "" {
+		base.PublishedDate = other.PublishedDate
+	}
+	if base.NumOfPages == 0 {
+		base.NumOfPages = other.NumOfPages
+	}
+	if base.ISBN10 == "" {
+		base.ISBN10 = other.ISBN10
+	}
+	if base.ISBN13 == "" {
+		base.ISBN13 = other.ISBN13
+	}
+	if len(base.Categories) == 0 {
+		base.Categories = other.Categories
+	}
+	if base.Description == "" {
+		base.Description = other.Description
+	}
+	if base.Publisher == "" {
+		base.Publisher = other.Publisher
+	}
+	if base.Language == "" {
+		base.Language = other.Language
+	}
+	if !base.IsForSale && other.IsForSale {
+		base.IsForSale = other.IsForSale
+		base.Price = other.Price
+		base.BuyLink = other.BuyLink
+	}
+	return base
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// 検索結果の書籍IDの一覧を返す
+func bookIDs(books []entity.ProviderBook) []string {
+	ids := []string{}
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids
+}
+
+func TestChainFallsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	failing := &Fake{ProviderName: "failing", Err: errors.New("unavailable")}
+	working := &Fake{ProviderName: "working", Books: []entity.ProviderBook{
+		{ID: "w1", Title: "Go言語入門", ISBN13: "9784000000001"},
+	}}
+	chain := NewChain(false, failing, working)
+
+	result, err := chain.Search(ctx, entity.ProviderSearchQuery{Query: "go"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := bookIDs(result.Books); !reflect.DeepEqual(got, []string{"w1"}) {
+		t.Errorf("Search() books = %v, want [w1]", got)
+	}
+	if result.Books[0].Provider != "working" {
+		t.Errorf("Search() provider = %q, want working", result.Books[0].Provider)
+	}
+
+	book, err := chain.LookupByISBN(ctx, "9784000000001")
+	if err != nil || book.ID != "w1" {
+		t.Errorf("LookupByISBN() = %q, %v, want w1", book.ID, err)
+	}
+	if failing.Calls() != 2 || working.Calls() != 2 {
+		t.Errorf("calls = %d, %d, want 2, 2", failing.Calls(), working.Calls())
+	}
+
+	// 全てのプロバイダで見つからなかった場合はErrNotFound
+	if _, err := NewChain(false, &Fake{}, &Fake{}).LookupByID(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LookupByID() error = %v, want ErrNotFound", err)
+	}
+
+	// 全てのプロバイダが失敗した場合は、全プロバイダのエラーをまとめて返す
+	_, err = NewChain(false, failing, &Fake{ProviderName: "other", Err: errors.New("timeout")}).Search(ctx, entity.ProviderSearchQuery{})
+	if err == nil || !strings.Contains(err.Error(), "failing: unavailable") || !strings.Contains(err.Error(), "other: timeout") {
+		t.Errorf("Search() error = %v, want errors of all providers", err)
+	}
+}
+
+func TestChainStopsAtFirstSuccess(t *testing.T) {
+	first := &Fake{ProviderName: "first"}
+	second := &Fake{ProviderName: "second", Books: []entity.ProviderBook{{ID: "s1", Title: "Go"}}}
+
+	// マージしない場合は、検索結果が0件でも成功した最初のプロバイダの結果を返す
+	result, err := NewChain(false, first, second).Search(context.Background(), entity.ProviderSearchQuery{Query: "go"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(result.Books) != 0 || second.Calls() != 0 {
+		t.Errorf("Search() books = %v, second calls = %d, want no books and no calls", bookIDs(result.Books), second.Calls())
+	}
+}
+
+func TestChainMergesResults(t *testing.T) {
+	google := &Fake{ProviderName: "googlebooks", Books: []entity.ProviderBook{
+		{ID: "g1", Title: "Go言語入門", Authors: []string{"山田太郎"}, ISBN13: "9784000000001", IsForSale: true, Price: 1200},
+		{ID: "g2", Title: "Go実践", Authors: []string{"佐藤花子"}},
+	}}
+	openLibrary := &Fake{ProviderName: "openlibrary", Books: []entity.ProviderBook{
+		{ID: "o1", Title: "Go言語入門 第2版", ISBN13: "9784000000001", NumOfPages: 320, Publisher: "技術出版"},
+		{ID: "o2", Title: "GO実践", Authors: []string{"佐藤花子"}, Description: "実践的な内容"},
+		{ID: "o3", Title: "Goによる並行処理", Authors: []string{"鈴木一郎"}},
+	}}
+
+	result, err := NewChain(true, google, openLibrary, &Fake{Err: errors.New("unavailable")}).
+		Search(context.Background(), entity.ProviderSearchQuery{Query: "go"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := bookIDs(result.Books); !reflect.DeepEqual(got, []string{"g1", "g2", "o3"}) {
+		t.Fatalf("Search() books = %v, want [g1 g2 o3]", got)
+	}
+	if result.TotalItems != 3 {
+		t.Errorf("Search() total = %d, want 3", result.TotalItems)
+	}
+
+	// 先のプロバイダの値を優先し、無い項目のみ後のプロバイダの値で補う
+	first := result.Books[0]
+	if first.Title != "Go言語入門" || first.NumOfPages != 320 || first.Publisher != "技術出版" || first.Price != 1200 {
+		t.Errorf("Search() merged book = %+v", first)
+	}
+	if result.Books[1].Description != "実践的な内容" {
+		t.Errorf("Search() merged description = %q, want 実践的な内容", result.Books[1].Description)
+	}
+}
+
+func TestNewFromEnvProviderOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order string
+		want  string
+	}{
+		{"未設定の場合はGoogle Booksのみ", "", "googlebooks"},
+		{"指定した順に問い合わせる", "openlibrary,googlebooks", "openlibrary,googlebooks"},
+		{"前後の空白は無視する", " googlebooks , openlibrary ", "googlebooks,openlibrary"},
+		{"不明なプロバイダは無視する", "unknown,openlibrary", "openlibrary"},
+		{"有効なプロバイダが無い場合はGoogle Booksを使う", "unknown", "googlebooks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOOK_PROVIDERS", tt.order)
+			t.Setenv("BOOK_CACHE_SIZE", "0")
+
+			chain, ok := NewFromEnv().(*Chain)
+			if !ok {
+				t.Fatalf("NewFromEnv() is not *Chain")
+			}
+			if got := chain.Name(); got != tt.want {
+				t.Errorf("NewFromEnv() providers = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainUsesProviderOrder(t *testing.T) {
+	book := entity.ProviderBook{ID: "b1", ISBN13: "9784000000001"}
+	first := &Fake{ProviderName: "first", Books: []entity.ProviderBook{book}}
+	second := &Fake{ProviderName: "second", Books: []entity.ProviderBook{book}}
+
+	for _, tt := range []struct {
+		providers []BookProvider
+		want      string
+	}{
+		{[]BookProvider{first, second}, "first"},
+		{[]BookProvider{second, first}, "second"},
+	} {
+		got, err := NewChain(false, tt.providers...).LookupByISBN(context.Background(), book.ISBN13)
+		if err != nil || got.Provider != tt.want {
+			t.Errorf("LookupByISBN() provider = %q, %v, want %q", got.Provider, err, tt.want)
+		}
+	}
+}
+
+func TestFakeSearch(t *testing.T) {
+	fake := &Fake{Books: []entity.ProviderBook{
+		{ID: "1", Title: "Go言語入門", Authors: []string{"山田太郎"}, Publisher: "技術出版", Language: "ja", PublishedDate: "2020-01-01", Categories: []string{"Computers"}, ISBN13: "9784000000001"},
+		{ID: "2", Title: "Learning Go", Authors: []string{"Jon Bodner"}, Publisher: "O'Reilly", Language: "en", PublishedDate: "2021-03-02", Categories: []string{"Computers"}, IsForSale: true},
+		{ID: "3", Title: "Goによる並行処理", Authors: []string{"鈴木一郎"}, Publisher: "技術出版", Language: "ja", PublishedDate: "2022-05-10", Categories: []string{"Programming"}, IsForSale: true},
+		{ID: "4", Title: "吾輩は猫である", Authors: []string{"夏目漱石"}, Publisher: "文学出版", Language: "ja", PublishedDate: "1905-01-01", Categories: []string{"Fiction"}},
+	}}
+
+	tests := []struct {
+		name      string
+		query     entity.ProviderSearchQuery
+		wantIDs   []string
+		wantTotal int
+	}{
+		{"検索ワード", entity.ProviderSearchQuery{Query: "go"}, []string{"1", "2", "3"}, 3},
+		{"1ページ目", entity.ProviderSearchQuery{Query: "go", PageSize: 2}, []string{"1", "2"}, 3},
+		{"2ページ目", entity.ProviderSearchQuery{Query: "go", Page: 2, PageSize: 2}, []string{"3"}, 3},
+		{"範囲外のページ", entity.ProviderSearchQuery{Query: "go", Page: 3, PageSize: 2}, []string{}, 3},
+		{"タイトル", entity.ProviderSearchQuery{Title: "learning"}, []string{"2"}, 1},
+		{"著者", entity.ProviderSearchQuery{Author: "夏目"}, []string{"4"}, 1},
+		{"ISBN", entity.ProviderSearchQuery{ISBN: "9784000000001"}, []string{"1"}, 1},
+		{"出版社", entity.ProviderSearchQuery{Publisher: "技術"}, []string{"1", "3"}, 2},
+		{"ジャンル", entity.ProviderSearchQuery{Subject: "computers"}, []string{"1", "2"}, 2},
+		{"言語", entity.ProviderSearchQuery{Language: "ja", Query: "go"}, []string{"1", "3"}, 2},
+		{"販売中", entity.ProviderSearchQuery{ForSale: true}, []string{"2", "3"}, 2},
+		{"電子書籍", entity.ProviderSearchQuery{EbookOnly: true, Language: "ja"}, []string{"3"}, 1},
+		{"新しい順", entity.ProviderSearchQuery{OrderBy: entity.SearchOrderNewest, PageSize: 3}, []string{"3", "2", "1"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fake.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got := bookIDs(result.Books); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("Search() books = %v, want %v", got, tt.wantIDs)
+			}
+			if result.TotalItems != tt.wantTotal {
+				t.Errorf("Search() total = %d, want %d", result.TotalItems, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// テスト用のプロバイダ(ネットワークにアクセスせず、Booksの中から検索する)
+type Fake struct {
+	ProviderName string
+	Books        []entity.ProviderBook
+	Err          error // 設定されている場合、全ての問い合わせでこのエラーを返す
+
+	mutex sync.Mutex
+	calls int
+}
+
+func (p *Fake) Name() string {
+	if p.ProviderName == "" {
+		return "fake"
+	}
+	return p.ProviderName
+}
+
+// Fakeの1ページあたりの件数の既定値
+const fakeDefaultPageSize = 10
+
+// 検索条件に一致する書籍を、指定されたページの分だけ返す
+// 検索ワードはタイトルまたは著者、その他の条件は各項目に含まれるか(言語は一致するか)で判定する
+// 書籍データに電子書籍かの項目が無いため、EbookOnlyは販売中の書籍(電子書籍として販売されているもの)のみとする
+func (p *Fake) Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error) {
+	p.countCall()
+	if p.Err != nil {
+		return entity.ProviderSearchResult{}, p.Err
+	}
+
+	matched := []entity.ProviderBook{}
+	for _, book := range p.Books {
+		if !fakeMatches(book, query) {
+			continue
+		}
+		if book.Provider == "" {
+			book.Provider = p.Name()
+		}
+		matched = append(matched, book)
+	}
+	if query.OrderBy == entity.SearchOrderNewest {
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].PublishedDate > matched[j].PublishedDate })
+	}
+
+	result := entity.ProviderSearchResult{TotalItems: len(matched)}
+	offset, limit := pageRange(query, fakeDefaultPageSize)
+	if offset < len(matched) {
+		end := offset + limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		result.Books = matched[offset:end]
+	}
+	return result, nil
+}
+
+// 書籍が検索条件に一致するか判定する
+func fakeMatches(book entity.ProviderBook, query entity.ProviderSearchQuery) bool {
+	contains := func(s, substr string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	}
+	authors := strings.Join(book.Authors, ",")
+	switch {
+	case query.Query != "" && !contains(book.Title, query.Query) && !contains(authors, query.Query):
+		return false
+	case query.Title != "" && !contains(book.Title, query.Title):
+		return false
+	case query.Author != "" && !contains(authors, query.Author):
+		return false
+	case query.ISBN != "" && book.ISBN10 != query.ISBN && book.ISBN13 != query.ISBN:
+		return false
+	case query.Publisher != "" && !contains(book.Publisher, query.Publisher):
+		return false
+	case query.Subject != "" && !contains(strings.Join(book.Categories, ","), query.Subject):
+		return false
+	case query.Language != "" && book.Language != query.Language:
+		return false
+	case (query.ForSale || query.EbookOnly) && !book.IsForSale:
+		return false
+	}
+	return true
+}
+
+func (p *Fake) LookupByID(ctx context.Context, id string) (entity.ProviderBook, error) {
+	return p.find(func(book entity.ProviderBook) bool { return book.ID == id })
+}
+
+func (p *Fake) LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error) {
+	return p.find(func(book entity.ProviderBook) bool { return book.ISBN10 == isbn || book.ISBN13 == isbn })
+}
+
+// 問い合わせ回数を返す
+func (p *Fake) Calls() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.calls
+}
+
+func (p *Fake) countCall() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.calls++
+}
+
+func (p *Fake) find(match func(entity.ProviderBook) bool) (entity.ProviderBook, error) {
+	p.countCall()
+	if p.Err != nil {
+		return entity.ProviderBook{}, p.Err
+	}
+	for _, book := range p.Books {
+		if match(book) {
+			if book.Provider == "" {
+				book.Provider = p.Name()
+			}
+			return book, nil
+		}
+	}
+	return entity.ProviderBook{}, ErrNotFound
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// Google Books APIのベースURL
+const googleBooksBaseURL = "https://www.googleapis.com/books/v1"
+
+// 1回の検索で取得する件数の既定値と上限(Google Books APIの仕様に合わせる)
+const (
+	googleBooksDefaultPageSize = 10
+	googleBooksMaxPageSize     = 40
+)
+
+// Google Books APIを用いるプロバイダ
+type GoogleBooks struct {
+	APIKey  string
+	BaseURL string
+	Client  *http.Client
+}
+
+// GoogleBooksを生成する(apiKeyは空でもよい)
+func NewGoogleBooks(apiKey string) *GoogleBooks {
+	return &GoogleBooks{APIKey: apiKey, BaseURL: googleBooksBaseURL, Client: httpClient}
+}
+
+func (p *GoogleBooks) Name() string {
+	return GoogleBooksName
+}
+
+func (p *GoogleBooks) Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error) {
+	params := url.Values{}
+	params.Set("q", googleBooksQuery(query))
+
+	// ページング
+	offset, limit := pageRange(query, googleBooksDefaultPageSize)
+	if limit > googleBooksMaxPageSize {
+		limit = googleBooksMaxPageSize
+	}
+	params.Set("startIndex", strconv.Itoa(offset))
+	params.Set("maxResults", strconv.Itoa(limit))
+
+	// 絞り込み、並び順
+	if query.Language != "" {
+		params.Set("langRestrict", query.Language)
+	}
+	if query.ForSale {
+		// 販売中の書籍は電子書籍に限られるため、ebookOnlyの指定に関わらず有料の電子書籍に絞り込む
+		params.Set("filter", "paid-ebooks")
+	} else if query.EbookOnly {
+		params.Set("filter", "ebooks")
+	}
+	if query.OrderBy != "" {
+		params.Set("orderBy", query.OrderBy)
+	}
+
+	var response entity.ResponseFromGoogleBooks
+	if err := getJSON(ctx, p.Client, p.url("/volumes", params), &response); err != nil {
+		return entity.ProviderSearchResult{}, err
+	}
+
+	// Google Books APIから受け取った書籍データを、プロバイダ共通の書籍構造体へと変換
+	result := entity.ProviderSearchResult{TotalItems: response.TotalItems}
+	for _, item := range response.Items {
+		result.Books = append(result.Books, p.toProviderBook(item))
+	}
+	return result, nil
+}
+
+func (p *GoogleBooks) LookupByID(ctx context.Context, id string) (entity.ProviderBook, error) {
+	var item entity.BookDataFromGoogleBooks
+	if err := getJSON(ctx, p.Client, p.url("/volumes/"+url.PathEscape(id), url.Values{}), &item); err != nil {
+		return entity.ProviderBook{}, err
+	}
+	return p.toProviderBook(item), nil
+}
+
+func (p *GoogleBooks) LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error) {
+	result, err := p.Search(ctx, entity.ProviderSearchQuery{ISBN: isbn})
+	if err != nil {
+		return entity.ProviderBook{}, err
+	}
+	if len(result.Books) == 0 {
+		return entity.ProviderBook{}, ErrNotFound
+	}
+	return result.Books[0], nil
+}
+
+// 検索ワードと検索項目の指定から、Google Books APIの検索クエリ(qパラメータ)を生成する
+// 例: "golang intitle:入門 inauthor:山田"
+func googleBooksQuery(query entity.ProviderSearchQuery) string {
+	terms := []string{}
+	if query.Query != "" {
+		terms = append(terms, query.Query)
+	}
+	qualifiers := []struct {
+		keyword string
+		value   string
+	}{
+		{"intitle", query.Title},
+		{"inauthor", query.Author},
+		{"isbn", query.ISBN},
+		{"inpublisher", query.Publisher},
+		{"subject", query.Subject},
+	}
+	for _, qualifier := range qualifiers {
+		value := strings.TrimSpace(qualifier.value)
+		if value == "" {
+			continue
+		}
+		// 空白を含む場合は、語句として扱われるようにダブルクォートで囲む
+		if strings.ContainsAny(value, " \t\u3000") {
+			value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+		}
+		terms = append(terms, qualifier.keyword+":"+value)
+	}
+	return strings.Join(terms, " ")
+}
+
+// APIキーを付与したリクエストURLを生成する
+func (p *GoogleBooks) url(path string, params url.Values) string {
+	if p.APIKey != "" {
+		params.Set("key", p.APIKey)
+	}
+	apiURL := p.BaseURL + path
+	if encoded := params.Encode(); encoded != "" {
+		apiURL += "?" + encoded
+	}
+	return apiURL
+}
+
+// Google Books APIの書籍データを、プロバイダ共通の書籍構造体へと変換する
+func (p *GoogleBooks) toProviderBook(item entity.BookDataFromGoogleBooks) entity.ProviderBook {
+	book := entity.ProviderBook{
+		ID:            item.ID,
+		Provider:      GoogleBooksName,
+		Title:         item.VolumeInfo.Title,
+		Authors:       item.VolumeInfo.Authors,
+		ThumbnailLink: item.VolumeInfo.ImageLinks.Thumbnail,
+		PublishedDate: item.VolumeInfo.PublishedDate,
+		NumOfPages:    item.VolumeInfo.PageCount,
+		Categories:    item.VolumeInfo.Categories,
+		Description:   item.VolumeInfo.Description,
+		Publisher:     item.VolumeInfo.Publisher,
+		Language:      item.VolumeInfo.Language,
+		IsForSale:     item.SaleInfo.IsEbook,
+		Price:         item.SaleInfo.RetailPrice.Amount,
+		BuyLink:       item.SaleInfo.BuyLink,
+	}
+	for _, identifier := range item.VolumeInfo.IndustryIdentifiers {
+		switch identifier.Type {
+		case "ISBN_10":
+			book.ISBN10 = identifier.Identifier
+		case "ISBN_13":
+			book.ISBN13 = identifier.Identifier
+		}
+	}
+	return book
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// Open Library APIのベースURL
+const (
+	openLibraryBaseURL   = "https://openlibrary.org"
+	openLibraryCoversURL = "https://covers.openlibrary.org"
+)
+
+// Open Library Search APIで取得する項目
+const openLibrarySearchFields = "key,title,author_name,cover_i,first_publish_year,number_of_pages_median,isbn,edition_key,subject,publisher,language"
+
+// 書籍の分類として取り込む件名の上限(Open Libraryの件名は非常に多いため)
+const openLibraryMaxSubjects = 10
+
+// 1回の検索で取得する件数の既定値(Google Books APIの既定値に合わせる)と上限
+const (
+	openLibraryDefaultPageSize = 10
+	openLibraryMaxPageSize     = 100
+)
+
+// ISO 639-1の言語コードから、Open Libraryで用いられる言語コード(MARC)への変換表
+var openLibraryLanguages = map[string]string{
+	"en": "eng",
+	"ja": "jpn",
+	"fr": "fre",
+	"de": "ger",
+	"es": "spa",
+	"it": "ita",
+	"pt": "por",
+	"ru": "rus",
+	"zh": "chi",
+	"ko": "kor",
+}
+
+// Open Library APIを用いるプロバイダ
+type OpenLibrary struct {
+	BaseURL   string
+	CoversURL string
+	Client    *http.Client
+}
+
+// OpenLibraryを生成する
+func NewOpenLibrary() *OpenLibrary {
+	return &OpenLibrary{BaseURL: openLibraryBaseURL, CoversURL: openLibraryCoversURL, Client: httpClient}
+}
+
+func (p *OpenLibrary) Name() string {
+	return OpenLibraryName
+}
+
+func (p *OpenLibrary) Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error) {
+	// Open Libraryは販売情報を持たないため、販売中の書籍での絞り込みには対応しない
+	if query.ForSale {
+		return entity.ProviderSearchResult{}, ErrUnsupportedQuery
+	}
+
+	params := url.Values{}
+	if query.Query != "" {
+		params.Set("q", query.Query)
+	}
+	params.Set("fields", openLibrarySearchFields)
+
+	// 検索項目の指定
+	fields := map[string]string{
+		"title":     query.Title,
+		"author":    query.Author,
+		"isbn":      query.ISBN,
+		"publisher": query.Publisher,
+		"subject":   query.Subject,
+	}
+	for field, value := range fields {
+		if value = strings.TrimSpace(value); value != "" {
+			params.Set(field, value)
+		}
+	}
+
+	// ページング
+	_, limit := pageRange(query, openLibraryDefaultPageSize)
+	if limit > openLibraryMaxPageSize {
+		limit = openLibraryMaxPageSize
+	}
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("limit", strconv.Itoa(limit))
+
+	// 絞り込み、並び順
+	if query.Language != "" {
+		language, ok := openLibraryLanguages[query.Language]
+		if !ok {
+			language = query.Language
+		}
+		params.Set("language", language)
+	}
+	if query.EbookOnly {
+		params.Set("has_fulltext", "true")
+	}
+	if query.OrderBy == entity.SearchOrderNewest {
+		params.Set("sort", "new")
+	}
+
+	var response entity.ResponseFromOpenLibrarySearch
+	if err := getJSON(ctx, p.Client, p.BaseURL+"/search.json?"+params.Encode(), &response); err != nil {
+		return entity.ProviderSearchResult{}, err
+	}
+
+	// Open Library APIから受け取った書籍データを、プロバイダ共通の書籍構造体へと変換
+	result := entity.ProviderSearchResult{TotalItems: response.NumFound}
+	for _, doc := range response.Docs {
+		book := entity.ProviderBook{
+			ID:         strings.TrimPrefix(doc.Key, "/works/"),
+			Provider:   OpenLibraryName,
+			Title:      doc.Title,
+			Authors:    doc.AuthorName,
+			NumOfPages: doc.NumberOfPagesMedian,
+		}
+		// 版(edition)のIDがある場合は、詳細取得に用いるためそちらを優先する
+		if len(doc.EditionKey) > 0 {
+			book.ID = doc.EditionKey[0]
+		}
+		if doc.CoverI > 0 {
+			book.ThumbnailLink = fmt.Sprintf("%s/b/id/%d-M.jpg", p.CoversURL, doc.CoverI)
+		}
+		if doc.FirstPublishYear > 0 {
+			book.PublishedDate = strconv.Itoa(doc.FirstPublishYear)
+		}
+		if len(doc.Subject) > 0 {
+			book.Categories = limitStrings(doc.Subject, openLibraryMaxSubjects)
+		}
+		if len(doc.Publisher) > 0 {
+			book.Publisher = doc.Publisher[0]
+		}
+		if len(doc.Language) > 0 {
+			book.Language = openLibraryLanguageCode(doc.Language[0])
+		}
+		for _, isbn := range doc.ISBN {
+			if len(isbn) == 13 && book.ISBN13 == "" {
+				book.ISBN13 = isbn
+			}
+			if len(isbn) == 10 && book.ISBN10 == "" {
+				book.ISBN10 = isbn
+			}
+		}
+		result.Books = append(result.Books, book)
+	}
+	return result, nil
+}
+
+func (p *OpenLibrary) LookupByID(ctx context.Context, id string) (entity.ProviderBook, error) {
+	return p.lookup(ctx, "OLID:"+id)
+}
+
+func (p *OpenLibrary) LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error) {
+	return p.lookup(ctx, "ISBN:"+isbn)
+}
+
+// Open Library Books APIで、書誌キー(OLID:xxx、ISBN:xxx)を指定して書籍を取得する
+func (p *OpenLibrary) lookup(ctx context.Context, bibkey string) (entity.ProviderBook, error) {
+	params := url.Values{}
+	params.Set("bibkeys", bibkey)
+	params.Set("format", "json")
+	params.Set("jscmd", "data")
+
+	var response map[string]entity.EditionDataFromOpenLibrary
+	if err := getJSON(ctx, p.Client, p.BaseURL+"/api/books?"+params.Encode(), &response); err != nil {
+		return entity.ProviderBook{}, err
+	}
+	edition, ok := response[bibkey]
+	if !ok {
+		return entity.ProviderBook{}, ErrNotFound
+	}
+
+	book := entity.ProviderBook{
+		ID:            strings.TrimPrefix(edition.Key, "/books/"),
+		Provider:      OpenLibraryName,
+		Title:         edition.Title,
+		ThumbnailLink: edition.Cover.Medium,
+		PublishedDate: edition.PublishDate,
+		NumOfPages:    edition.NumberOfPages,
+	}
+	for _, author := range edition.Authors {
+		book.Authors = append(book.Authors, author.Name)
+	}
+	for _, subject := range edition.Subjects {
+		book.Categories = append(book.Categories, subject.Name)
+	}
+	book.Categories = limitStrings(book.Categories, openLibraryMaxSubjects)
+	if len(edition.Publishers) > 0 {
+		book.Publisher = edition.Publishers[0].Name
+	}
+	if len(edition.Identifiers.ISBN10) > 0 {
+		book.ISBN10 = edition.Identifiers.ISBN10[0]
+	}
+	if len(edition.Identifiers.ISBN13) > 0 {
+		book.ISBN13 = edition.Identifiers.ISBN13[0]
+	}
+	return book, nil
+}
+
+// Open Libraryの言語コード(MARC)を、ISO 639-1の言語コードに変換する(変換表に無い場合はそのまま返す)
+func openLibraryLanguageCode(language string) string {
+	for code, marc := range openLibraryLanguages {
+		if marc == language {
+			return code
+		}
+	}
+	return language
+}
+
+// スライスの先頭から、最大n件を返す
+func limitStrings(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// 書籍メタデータプロバイダのインターフェース
+type BookProvider interface {
+	// プロバイダ名を返す
+	Name() string
+	// 検索条件に一致する書籍を検索する
+	Search(ctx context.Context, query entity.ProviderSearchQuery) (entity.ProviderSearchResult, error)
+	// プロバイダ固有のIDで書籍を取得する
+	LookupByID(ctx context.Context, id string) (entity.ProviderBook, error)
+	// ISBN(10桁または13桁)で書籍を取得する
+	LookupByISBN(ctx context.Context, isbn string) (entity.ProviderBook, error)
+}
+
+// 書籍が見つからない場合のエラー
+var ErrNotFound = errors.New("book not found")
+
+// プロバイダが対応していない検索条件が指定された場合のエラー
+var ErrUnsupportedQuery = errors.New("search query is not supported by the provider")
+
+// プロバイダ名
+const (
+	GoogleBooksName = "googlebooks"
+	OpenLibraryName = "openlibrary"
+)
+
+// 環境変数BOOK_PROVIDERSが未設定の場合のプロバイダの順序
+const defaultProviderOrder = GoogleBooksName
+
+// 環境変数が未設定の場合のキャッシュの設定
+const (
+	defaultCacheSize = 1000
+	defaultCacheTTL  = time.Hour
+)
+
+// 外部APIへのリクエストに用いるHTTPクライアント
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+var (
+	defaultProvider BookProvider
+	defaultMutex    sync.Mutex
+)
+
+// アプリケーション全体で用いるプロバイダを返す(初回呼び出し時に環境変数の設定から生成する)
+func Default() BookProvider {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+
+	if defaultProvider == nil {
+		defaultProvider = NewFromEnv()
+	}
+	return defaultProvider
+}
+
+// アプリケーション全体で用いるプロバイダを差し替える(テストでFakeを用いる場合など)
+func SetDefault(p BookProvider) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+
+	defaultProvider = p
+}
+
+// 環境変数の設定からプロバイダを生成する
+//   - BOOK_PROVIDERS: 問い合わせるプロバイダをカンマ区切りで優先順に指定(例: "googlebooks,openlibrary")
+//   - BOOK_PROVIDERS_MERGE: trueの場合は全プロバイダの検索結果をマージする(falseの場合は最初に成功した結果を返す)
+//   - GOOGLE_BOOKS_API_KEY: Google Books APIのAPIキー(任意)
+//   - BOOK_CACHE_SIZE: メモリ上にキャッシュする問い合わせ結果の件数(0の場合はキャッシュしない)
+//   - BOOK_CACHE_TTL: キャッシュの有効期間(例: "30m")
+//   - BOOK_CACHE_DB: trueの場合はキャッシュをPostgresのテーブルにも保存する
+func NewFromEnv() BookProvider {
+	order := os.Getenv("BOOK_PROVIDERS")
+	if order == "" {
+		order = defaultProviderOrder
+	}
+	merge, _ := strconv.ParseBool(os.Getenv("BOOK_PROVIDERS_MERGE"))
+
+	var providers []BookProvider
+	for _, name := range strings.Split(order, ",") {
+		switch strings.TrimSpace(name) {
+		case GoogleBooksName:
+			providers = append(providers, NewGoogleBooks(os.Getenv("GOOGLE_BOOKS_API_KEY")))
+		case OpenLibraryName:
+			providers = append(providers, NewOpenLibrary())
+		}
+	}
+	if len(providers) == 0 {
+		providers = append(providers, NewGoogleBooks(os.Getenv("GOOGLE_BOOKS_API_KEY")))
+	}
+
+	chain := NewChain(merge, providers...)
+
+	// キャッシュの設定
+	cacheSize := defaultCacheSize
+	if size, err := strconv.Atoi(os.Getenv("BOOK_CACHE_SIZE")); err == nil {
+		cacheSize = size
+	}
+	if cacheSize <= 0 {
+		return chain
+	}
+	cacheTTL := defaultCacheTTL
+	if ttl, err := time.ParseDuration(os.Getenv("BOOK_CACHE_TTL")); err == nil {
+		cacheTTL = ttl
+	}
+	var store CacheStore
+	if useDB, _ := strconv.ParseBool(os.Getenv("BOOK_CACHE_DB")); useDB && db.GetDB() != nil {
+		dbStore := DBCacheStore{DB: db.GetDB()}
+		// 起動時に有効期限切れのキャッシュを削除しておく
+		go func() {
+			if err := dbStore.DeleteExpired(time.Now()); err != nil {
+				log.Println(err)
+			}
+		}()
+		store = dbStore
+	}
+
+	return NewCache(chain, cacheSize, cacheTTL, store)
+}
+
+// 検索条件のページ番号と1ページあたりの件数を、取得開始位置と取得件数に変換する
+func pageRange(query entity.ProviderSearchQuery, defaultPageSize int) (int, int) {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * pageSize, pageSize
+}
+
+// 外部APIにGETリクエストを送り、レスポンス(JSON)をvに変換する
+func getJSON(ctx context.Context, client *http.Client, apiURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", req.URL.Host, res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
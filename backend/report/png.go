@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// 年間の読書のまとめを、シェア用のPNG画像として出力する
+// 日本語のフォントを持たないため、数値と英字のラベル、月別のグラフのみを描画する(書籍のタイトル等はSVG画像、HTMLページで表示する)
+func PNG(summary entity.YearInReview) ([]byte, error) {
+	background, accent := parseColor(backgroundColor), parseColor(accentColor)
+	text, subText := parseColor(textColor), parseColor(subTextColor)
+
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	fillRect(img, 0, 0, cardWidth, cardHeight, background)
+	drawText(img, chartLeft, 56, 7, text, "YEAR IN REVIEW "+strconv.Itoa(summary.Year))
+
+	numbers := []struct{ Label, Value string }{
+		{"BOOKS", formatNumber(summary.NumOfReadBooks)},
+		{"PAGES", formatNumber(summary.NumOfReadPages)},
+		{"AVG RATING", formatRating(summary.AverageRating)},
+	}
+	for i, number := range numbers {
+		x := chartLeft + i*360
+		drawText(img, x, 150, 3, subText, number.Label)
+		drawText(img, x, 185, 9, accent, number.Value)
+	}
+
+	for _, bar := range monthBars(summary.Monthly, chartLeft, chartRight, chartTop, chartBottom) {
+		fillRect(img, bar.X, bar.Y, bar.Width, bar.Height, accent)
+		drawText(img, bar.Center-textWidth(bar.Label, 3)/2, chartBottom+12, 3, subText, bar.Label)
+		if bar.Value > 0 {
+			value := strconv.FormatInt(bar.Value, 10)
+			drawText(img, bar.Center-textWidth(value, 3)/2, bar.Y-30, 3, text, value)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// "#rrggbb"形式の色を変換する
+func parseColor(hex string) color.RGBA {
+	value, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}
+}
@@ -0,0 +1,187 @@
+// 年間の読書のまとめを、HTMLページ、SVG/PNGのシェア用画像として出力する
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// シェア用画像のサイズ(SNSのプレビュー画像の推奨サイズ)
+const (
+	cardWidth  = 1200
+	cardHeight = 630
+)
+
+// シェア用画像の月別のグラフの配置
+const (
+	chartLeft   = 60
+	chartRight  = 1140
+	chartTop    = 330
+	chartBottom = 540
+)
+
+// シェア用画像の配色
+const (
+	backgroundColor = "#1e2a44"
+	accentColor     = "#f6b93b"
+	textColor       = "#ffffff"
+	subTextColor    = "#aab4c8"
+)
+
+// グラフの棒
+type bar struct {
+	Label  string // 月(1〜12)
+	Value  int64  // 読了した冊数
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Center int // 棒の中央のX座標(ラベルの配置用)
+}
+
+// 月別の読了冊数を、指定した領域に収まる棒グラフの棒に変換する
+func monthBars(monthly []entity.MonthlyStats, left, right, top, bottom int) []bar {
+	var max int64
+	for _, month := range monthly {
+		if month.NumOfReadBooks > max {
+			max = month.NumOfReadBooks
+		}
+	}
+
+	bars := []bar{}
+	if len(monthly) == 0 {
+		return bars
+	}
+	slot := (right - left) / len(monthly)
+	for i, month := range monthly {
+		height := 0
+		if max > 0 {
+			height = int(month.NumOfReadBooks * int64(bottom-top) / max)
+		}
+		bars = append(bars, bar{
+			Label:  strconv.Itoa(i + 1),
+			Value:  month.NumOfReadBooks,
+			X:      left + slot*i + slot/6,
+			Y:      bottom - height,
+			Width:  slot * 2 / 3,
+			Height: height,
+			Center: left + slot*i + slot/2,
+		})
+	}
+	return bars
+}
+
+// 数値を3桁区切りの文字列にする
+func formatNumber(n int64) string {
+	if n < 0 {
+		return "-" + formatNumber(-n)
+	}
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// 評価を小数点以下1桁の文字列にする
+func formatRating(rating float64) string {
+	return strconv.FormatFloat(rating, 'f', 1, 64)
+}
+
+// 年間の読書のまとめのHTMLページのテンプレート
+// 外部のリソースを参照せず、1ファイルで表示できるようにする
+var htmlTemplate = template.Must(template.New("yearInReview").Funcs(template.FuncMap{
+	"number": formatNumber,
+	"rating": formatRating,
+	"pages":  func(n uint) int64 { return int64(n) },
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Summary.Year}}年の読書</title>
+<style>
+body { margin: 0; background: #f4f5f7; color: #1e2a44; font-family: sans-serif; }
+main { max-width: 880px; margin: 0 auto; padding: 32px 16px; }
+header { background: #1e2a44; color: #ffffff; border-radius: 12px; padding: 32px; }
+header h1 { margin: 0 0 8px; font-size: 32px; }
+header p { margin: 0; color: #aab4c8; }
+.numbers { display: flex; gap: 16px; margin-top: 24px; }
+.numbers div { flex: 1; }
+.numbers strong { display: block; font-size: 40px; color: #f6b93b; }
+section { background: #ffffff; border-radius: 12px; padding: 24px 32px; margin-top: 16px; }
+h2 { margin: 0 0 16px; font-size: 20px; }
+ol, ul { margin: 0; padding-left: 24px; }
+li { margin: 4px 0; }
+.muted { color: #6b7489; }
+.books { display: flex; gap: 16px; }
+.books div { flex: 1; }
+</style>
+</head>
+<body>
+<main>
+<header>
+<h1>{{.Summary.Year}}年の読書</h1>
+{{if .Summary.UserName}}<p>{{.Summary.UserName}}さんの1年間の記録</p>{{end}}
+<div class="numbers">
+<div>読んだ本<strong>{{number .Summary.NumOfReadBooks}}冊</strong></div>
+<div>読んだページ<strong>{{number .Summary.NumOfReadPages}}</strong></div>
+<div>平均評価<strong>{{rating .Summary.AverageRating}}</strong></div>
+</div>
+</header>
+<section>
+<h2>月別の読了冊数</h2>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 {{.ChartWidth}} {{.ChartHeight}}" width="100%" role="img">
+{{range .Bars}}<rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" rx="4" fill="#f6b93b"/>
+<text x="{{.Center}}" y="{{$.ChartHeight}}" dy="-4" font-size="14" fill="#6b7489" text-anchor="middle">{{.Label}}月</text>
+{{if .Value}}<text x="{{.Center}}" y="{{.Y}}" dy="-6" font-size="14" fill="#1e2a44" text-anchor="middle">{{.Value}}</text>{{end}}
+{{end}}</svg>
+</section>
+{{if .Summary.TopRatedBooks}}<section>
+<h2>評価の高かった本</h2>
+<ol>
+{{range .Summary.TopRatedBooks}}<li>{{.Title}} <span class="muted">{{.Author}} / ★{{rating .Rating}}</span></li>
+{{end}}</ol>
+</section>{{end}}
+{{if or .Summary.FirstBook .Summary.LongestBook}}<section class="books">
+{{with .Summary.FirstBook}}<div><h2>最初の1冊</h2>{{.Title}}<br><span class="muted">{{.Author}} / {{.FinishReadAt}}</span></div>{{end}}
+{{with .Summary.LastBook}}<div><h2>最後の1冊</h2>{{.Title}}<br><span class="muted">{{.Author}} / {{.FinishReadAt}}</span></div>{{end}}
+{{with .Summary.LongestBook}}<div><h2>最も長い本</h2>{{.Title}}<br><span class="muted">{{.Author}} / {{number (pages .NumOfPages)}}ページ</span></div>{{end}}
+</section>{{end}}
+{{if or .Summary.FavoriteAuthors .Summary.FavoriteTags}}<section class="books">
+{{if .Summary.FavoriteAuthors}}<div><h2>よく読んだ著者</h2><ol>
+{{range .Summary.FavoriteAuthors}}<li>{{.Name}} <span class="muted">{{.NumOfReadBooks}}冊</span></li>
+{{end}}</ol></div>{{end}}
+{{if .Summary.FavoriteTags}}<div><h2>よく付けたタグ</h2><ol>
+{{range .Summary.FavoriteTags}}<li>{{.Name}} <span class="muted">{{.NumOfReadBooks}}冊</span></li>
+{{end}}</ol></div>{{end}}
+</section>{{end}}
+</main>
+</body>
+</html>
+`))
+
+// 年間の読書のまとめを、HTMLページとして出力する
+func HTML(summary entity.YearInReview) ([]byte, error) {
+	const chartWidth, chartHeight = 800, 240
+	data := struct {
+		Summary     entity.YearInReview
+		Bars        []bar
+		ChartWidth  int
+		ChartHeight int
+	}{
+		Summary:     summary,
+		Bars:        monthBars(summary.Monthly, 0, chartWidth, 24, chartHeight-24),
+		ChartWidth:  chartWidth,
+		ChartHeight: chartHeight,
+	}
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
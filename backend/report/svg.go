@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// シェア用画像に表示する書籍のタイトルの最大の文字数
+const cardTitleLength = 30
+
+// 年間の読書のまとめを、シェア用のSVG画像として出力する
+func SVG(summary entity.YearInReview) []byte {
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`, cardWidth, cardHeight, cardWidth, cardHeight)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`, backgroundColor)
+	fmt.Fprintf(&svg, `<text x="60" y="100" fill="%s" font-size="56" font-weight="bold">%d年の読書</text>`, textColor, summary.Year)
+	if summary.UserName != "" {
+		fmt.Fprintf(&svg, `<text x="%d" y="100" fill="%s" font-size="28" text-anchor="end">%s</text>`, chartRight, subTextColor, html.EscapeString(summary.UserName))
+	}
+
+	numbers := []struct{ Label, Value string }{
+		{"読んだ本", formatNumber(summary.NumOfReadBooks) + "冊"},
+		{"読んだページ", formatNumber(summary.NumOfReadPages)},
+		{"平均評価", formatRating(summary.AverageRating)},
+	}
+	for i, number := range numbers {
+		x := chartLeft + i*360
+		fmt.Fprintf(&svg, `<text x="%d" y="170" fill="%s" font-size="24">%s</text>`, x, subTextColor, number.Label)
+		fmt.Fprintf(&svg, `<text x="%d" y="250" fill="%s" font-size="72" font-weight="bold">%s</text>`, x, accentColor, number.Value)
+	}
+
+	for _, bar := range monthBars(summary.Monthly, chartLeft, chartRight, chartTop, chartBottom) {
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s"/>`, bar.X, bar.Y, bar.Width, bar.Height, accentColor)
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="%s" font-size="20" text-anchor="middle">%s</text>`, bar.Center, chartBottom+28, subTextColor, bar.Label)
+		if bar.Value > 0 {
+			fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="%s" font-size="20" text-anchor="middle">%s</text>`, bar.Center, bar.Y-8, textColor, strconv.FormatInt(bar.Value, 10))
+		}
+	}
+
+	if len(summary.TopRatedBooks) > 0 {
+		fmt.Fprintf(&svg, `<text x="60" y="605" fill="%s" font-size="22">ベスト: %s</text>`, textColor, html.EscapeString(truncate(summary.TopRatedBooks[0].Title, cardTitleLength)))
+	}
+	svg.WriteString(`</svg>`)
+	return []byte(svg.String())
+}
+
+// 文字列を指定した文字数で切り詰める(切り詰めた場合は末尾に"…"を付ける)
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
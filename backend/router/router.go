@@ -34,19 +34,132 @@ func router() *gin.Engine {
 	// レビュー関連のルーティング
 	reviewRouter := r.Group("/review")
 	{
+		// /review/?page=[ページ]&genre=[ジャンル]&includeQuotes=[true|false]
 		reviewRouter.GET("/", controller.GetReviews)
 		reviewRouter.POST("/", controller.CreateReview)
 		reviewRouter.PATCH("/:id", controller.UpdateReview)
 		reviewRouter.DELETE("/:id", controller.DeleteReview)
+		reviewRouter.POST("/bulk", controller.BulkUpdateReviews)
+		// /review/statistics?year=[年]&month=[1〜12](未指定の場合はユーザのタイムゾーンでの今年、今月)
 		reviewRouter.GET("/statistics", controller.GetReviewStats)
+		// /review/statistics/detail?from=[YYYY-MM]&to=[YYYY-MM]
+		reviewRouter.GET("/statistics/detail", controller.GetDetailedReviewStats)
+		// ヒートマップ用の日別の活動と連続記録
+		// /review/activity?from=[YYYY-MM-DD]&to=[YYYY-MM-DD]&tz=[タイムゾーン名(例: Asia/Tokyo。未指定の場合はユーザのタイムゾーン)]
+		reviewRouter.GET("/activity", controller.GetReadingActivity)
+		// 読書中の書籍の進捗と読了見込み日(読了に近い順)
+		// /review/currently-reading?days=[読書ペースの算出に用いる日数]
+		reviewRouter.GET("/currently-reading", controller.GetCurrentlyReading)
+		// /review/year-in-review/[年]?format=[json|html|svg|png]
+		reviewRouter.GET("/year-in-review/:year", controller.GetYearInReview)
+		// /review/export?format=[csv|json|md]&tag=[タグ]&readingStatus=[読書ステータス]&genre=[ジャンル]
+		reviewRouter.GET("/export", controller.ExportReviews)
 		reviewRouter.GET("/tags/:tagName", controller.FilterReviewByTag)
+		reviewRouter.GET("/:id/quotes", controller.GetQuotes)
+		reviewRouter.POST("/:id/quotes", controller.CreateQuote)
+		reviewRouter.PATCH("/:id/quotes/:quoteId", controller.UpdateQuote)
+		reviewRouter.DELETE("/:id/quotes/:quoteId", controller.DeleteQuote)
+		// /review/import/goodreads?dryRun=[true|false]
+		reviewRouter.POST("/import/goodreads", controller.ImportGoodreads)
+		// /review/import/kindle?dryRun=[true|false]
+		reviewRouter.POST("/import/kindle", controller.ImportKindleClippings)
+		reviewRouter.GET("/import/:jobId", controller.GetImportJob)
+	}
+
+	// 引用関連のルーティング
+	quoteRouter := r.Group("/quote")
+	{
+		// /quote?search=[検索ワード]&tag=[タグ名]
+		quoteRouter.GET("/", controller.SearchQuotes)
 	}
 
 	// 書籍関連のルーティング
 	bookRouter := r.Group("/book")
 	{
-		// /book?search=[検索ワード]
+		// /book?search=[検索ワード]&title=[タイトル]&author=[著者]&isbn=[ISBN]&publisher=[出版社]&subject=[分野]
+		//   &lang=[言語コード]&ebook=[true|false]&forSale=[true|false]&orderBy=[relevance|newest]
+		//   &page=[ページ番号]&pageSize=[1ページあたりの件数]&noCache=[true|false]
 		bookRouter.GET("/", controller.SearchBooks)
+		// 書籍メタデータプロバイダに無い書籍の手動登録
+		bookRouter.POST("/", controller.CreateBook)
+		// /book/isbn/[ISBN-10、またはISBN-13(ハイフン可)]
+		bookRouter.GET("/isbn/:isbn", controller.GetBookByISBN)
+		// 書籍メタデータのキャッシュの統計情報(モデレータのみ)
+		bookRouter.GET("/cache/stats", controller.GetBookCacheStats)
+		// 書籍データの修正提案の承認、却下(モデレータのみ)
+		// /book/edits?status=[pending|approved|rejected]
+		bookRouter.GET("/edits", controller.GetBookEditQueue)
+		bookRouter.POST("/edits/:editId/approve", controller.ApproveBookEdit)
+		bookRouter.POST("/edits/:editId/reject", controller.RejectBookEdit)
+		// 書籍の重複候補の確認、統合(モデレータのみ)
+		// /book/duplicates?status=[pending|dismissed]&minScore=[類似度の下限]
+		bookRouter.GET("/duplicates", controller.GetDuplicateCandidates)
+		bookRouter.POST("/duplicates/scan", controller.ScanDuplicateBooks)
+		bookRouter.POST("/duplicates/:candidateId/dismiss", controller.DismissDuplicateCandidate)
+		bookRouter.GET("/:id", controller.GetBook)
+		// /book/[書籍ID]/cover?size=[small|medium|large]
+		bookRouter.GET("/:id/cover", controller.GetBookCover)
+		// /book/[書籍ID]/edits?status=[pending|approved|rejected]
+		bookRouter.GET("/:id/edits", controller.GetBookEdits)
+		bookRouter.POST("/:id/edits", controller.SuggestBookEdit)
+		bookRouter.GET("/:id/history", controller.GetBookHistory)
+		// 重複した書籍を統合(モデレータのみ)
+		bookRouter.POST("/:id/merge", controller.MergeBooks)
+		bookRouter.GET("/:id/prices", controller.GetBookPrices)
+		// 価格が目標価格以下になった場合に通知する
+		bookRouter.POST("/:id/price-watch", controller.WatchBookPrice)
+		bookRouter.DELETE("/:id/price-watch", controller.UnwatchBookPrice)
+	}
+
+	// 読書目標関連のルーティング
+	goalRouter := r.Group("/goals")
+	{
+		// /goals?active=[true|false]
+		goalRouter.GET("/", controller.GetGoals)
+		goalRouter.POST("/", controller.CreateGoal)
+		goalRouter.GET("/:id", controller.GetGoal)
+		goalRouter.PATCH("/:id", controller.UpdateGoal)
+		goalRouter.DELETE("/:id", controller.DeleteGoal)
+	}
+
+	// 通知関連のルーティング
+	notificationRouter := r.Group("/notification")
+	{
+		// /notification?unread=[true|false]
+		notificationRouter.GET("/", controller.GetNotifications)
+		// 既読にする
+		notificationRouter.PATCH("/:id", controller.ReadNotification)
+	}
+
+	// 作品関連のルーティング
+	workRouter := r.Group("/work")
+	{
+		workRouter.GET("/:id", controller.GetWork)
+	}
+
+	// シリーズ関連のルーティング
+	seriesRouter := r.Group("/series")
+	{
+		seriesRouter.POST("/", controller.CreateSeries)
+		// ログインユーザが次に読む巻の一覧
+		seriesRouter.GET("/next", controller.GetNextInSeries)
+		seriesRouter.GET("/:id", controller.GetSeries)
+		seriesRouter.POST("/:id/entries", controller.AddSeriesEntry)
+		seriesRouter.DELETE("/:id/entries/:entryId", controller.DeleteSeriesEntry)
+	}
+
+	// ジャンル関連のルーティング
+	genreRouter := r.Group("/genre")
+	{
+		genreRouter.GET("/", controller.GetGenres)
+	}
+
+	// 著者関連のルーティング
+	authorRouter := r.Group("/author")
+	{
+		authorRouter.GET("/:id", controller.GetAuthor)
+		// 著者の別名登録(モデレータのみ)
+		authorRouter.POST("/:id/aliases", controller.AddAuthorAlias)
 	}
 
 	// 認証関連のルーティング
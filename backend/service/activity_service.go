@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type ReadingActivity entity.ReadingActivity
+
+// 読書の活動状況の期間の設定
+const (
+	defaultActivityDays = 365     // 期間が指定されていない場合の日数(今日まで)
+	maxActivityDays     = 366 * 3 // 指定できる期間の最大の日数
+)
+
+// 読書の活動状況取得サービス
+// ?from=[YYYY-MM-DD]&to=[YYYY-MM-DD]&tz=[タイムゾーン名]で対象期間と日付の集計に用いるタイムゾーンを指定する(未指定の場合はユーザのタイムゾーン)
+// 日別の活動は期間内、連続記録はこれまでの全ての活動から求める
+func (s Service) GetReadingActivity(c *gin.Context) (entity.ReadingActivityResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ReadingActivityResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ReadingActivityResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ReadingActivityResponse{}, http.StatusNotFound, err
+	}
+
+	// タイムゾーン、対象期間を決定
+	location := userLocation(user)
+	if timeZone := c.Query("tz"); timeZone != "" {
+		location, err = loadTimeZone(timeZone)
+		if err != nil {
+			return entity.ReadingActivityResponse{}, http.StatusBadRequest, err
+		}
+	}
+	today := dateOf(time.Now().In(location))
+	from, to, err := activityDateRange(c.Query("from"), c.Query("to"), today)
+	if err != nil {
+		return entity.ReadingActivityResponse{}, http.StatusBadRequest, err
+	}
+
+	// レビューの登録日時と読書記録を取得
+	var reviewCreatedAts []int64
+	if err := db.Model(&Review{}).Where("user_id = ?", user.ID).Pluck("created_at", &reviewCreatedAts).Error; err != nil {
+		// SELECT created_at FROM reviews WHERE user_id = [user.ID];
+		return entity.ReadingActivityResponse{}, http.StatusInternalServerError, err
+	}
+	var activities []ReadingActivity
+	if err := db.Select("type", "pages", "created_at").Where("user_id = ?", user.ID).Find(&activities).Error; err != nil {
+		// SELECT type, pages, created_at FROM reading_activities WHERE user_id = [user.ID];
+		return entity.ReadingActivityResponse{}, http.StatusInternalServerError, err
+	}
+
+	response := buildReadingActivity(reviewCreatedAts, activities, location, from, to, today)
+	response.TimeZone = location.String()
+	return response, http.StatusOK, nil
+}
+
+// レビューの登録日時と読書記録を、タイムゾーンでの日付ごとに集計して活動状況を返す
+func buildReadingActivity(reviewCreatedAts []int64, activities []ReadingActivity, location *time.Location, from, to, today time.Time) entity.ReadingActivityResponse {
+	days := map[string]*entity.DailyActivity{}
+	dayOf := func(unix int64) *entity.DailyActivity {
+		date := time.Unix(unix, 0).In(location).Format("2006-01-02")
+		day, ok := days[date]
+		if !ok {
+			day = &entity.DailyActivity{Date: date}
+			days[date] = day
+		}
+		day.Count++
+		return day
+	}
+	for _, createdAt := range reviewCreatedAts {
+		dayOf(createdAt).ReviewsCreated++
+	}
+	for _, activity := range activities {
+		day := dayOf(activity.CreatedAt)
+		switch activity.Type {
+		case entity.ReadingActivityStatusChanged:
+			day.StatusChanges++
+		case entity.ReadingActivityPagesLogged:
+			day.PagesLogged += int64(activity.Pages)
+		}
+	}
+
+	response := entity.ReadingActivityResponse{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: []entity.DailyActivity{},
+	}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := entity.DailyActivity{Date: date.Format("2006-01-02")}
+		if activity, ok := days[day.Date]; ok {
+			day = *activity
+		}
+		if day.Count > response.MaxCount {
+			response.MaxCount = day.Count
+		}
+		response.Days = append(response.Days, day)
+	}
+
+	// 活動した日を古い順に並べ、連続した日数を数える
+	var dates []time.Time
+	for date := range days {
+		parsed, _ := time.Parse(dateLayout, date)
+		dates = append(dates, parsed)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	streak := 0
+	for i, date := range dates {
+		if i > 0 && daysBetween(dates[i-1], date) == 1 {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak > response.LongestStreak {
+			response.LongestStreak = streak
+			response.LongestStreakStart = date.AddDate(0, 0, -(streak - 1)).Format("2006-01-02")
+			response.LongestStreakEnd = date.Format("2006-01-02")
+		}
+	}
+
+	// 今日の活動が無くても、昨日まで連続していれば継続中とする
+	date := today
+	if _, ok := days[date.Format("2006-01-02")]; !ok {
+		date = date.AddDate(0, 0, -1)
+	}
+	for {
+		if _, ok := days[date.Format("2006-01-02")]; !ok {
+			break
+		}
+		response.CurrentStreak++
+		date = date.AddDate(0, 0, -1)
+	}
+	return response
+}
+
+// レビューの読書ステータス、読んだページ数の変化を読書記録として登録する
+// レビューの新規登録時は、登録前のレビューに読書ステータスのみ同じ値を設定して呼び出す(読んだページ数のみ記録する)
+func recordReadingActivities(tx *gorm.DB, before, after Review) error {
+	var activities []ReadingActivity
+	if before.ReadingStatus != after.ReadingStatus {
+		activities = append(activities, ReadingActivity{
+			Type:          entity.ReadingActivityStatusChanged,
+			ReadingStatus: after.ReadingStatus,
+			ReviewID:      after.ID,
+			UserID:        after.UserID,
+		})
+	}
+	if after.ReadPages > before.ReadPages {
+		activities = append(activities, ReadingActivity{
+			Type:          entity.ReadingActivityPagesLogged,
+			ReadingStatus: after.ReadingStatus,
+			Pages:         after.ReadPages - before.ReadPages,
+			ReviewID:      after.ID,
+			UserID:        after.UserID,
+		})
+	}
+	if len(activities) == 0 {
+		return nil
+	}
+	return tx.Create(&activities).Error
+}
+
+// 読書の活動状況の対象期間の最初の日と最後の日を返す
+func activityDateRange(fromParam, toParam string, today time.Time) (time.Time, time.Time, error) {
+	to := today
+	if toParam != "" {
+		parsed, err := time.Parse(dateLayout, toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(defaultActivityDays - 1))
+	if fromParam != "" {
+		parsed, err := time.Parse(dateLayout, fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	if daysBetween(from, to)+1 > maxActivityDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("period must be at most %d days", maxActivityDays)
+	}
+	return from, to, nil
+}
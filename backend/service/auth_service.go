@@ -54,6 +54,15 @@ func (s Service) Register(c *gin.Context) (User, StatusCode, error) {
 		Name:     request.Name,
 		Email:    request.Email,
 		Password: string(hashedPassword),
+		TimeZone: defaultTimeZone,
+	}
+
+	// タイムゾーンの検証
+	if request.TimeZone != "" {
+		if _, err := loadTimeZone(request.TimeZone); err != nil {
+			return User{}, http.StatusBadRequest, err
+		}
+		newUser.TimeZone = request.TimeZone
 	}
 
 	if err := db.Create(&newUser).Error; err != nil {
@@ -146,6 +155,12 @@ func (s Service) UpdateAccount(c *gin.Context) (User, StatusCode, error) {
 	user.Name = request.NewName
 	user.Email = request.NewEmail
 	user.Password = string(hashedNewPassword)
+	if request.NewTimeZone != "" {
+		if _, err := loadTimeZone(request.NewTimeZone); err != nil {
+			return User{}, http.StatusBadRequest, err
+		}
+		user.TimeZone = request.NewTimeZone
+	}
 	db.Save(&user)
 
 	return user, http.StatusOK, nil
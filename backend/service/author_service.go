@@ -0,0 +1,302 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Author entity.Author
+type AddAuthorAliasRequest entity.AddAuthorAliasRequest
+
+// 著者名の後ろに括弧書きされた役割の表記と、役割の対応
+// 例: "山田太郎(訳)"、"John Smith (Illustrator)"
+var contributorRoleSuffixes = map[string]string{
+	"著":           entity.AuthorRoleAuthor,
+	"author":      entity.AuthorRoleAuthor,
+	"訳":           entity.AuthorRoleTranslator,
+	"翻訳":          entity.AuthorRoleTranslator,
+	"translator":  entity.AuthorRoleTranslator,
+	"絵":           entity.AuthorRoleIllustrator,
+	"イラスト":        entity.AuthorRoleIllustrator,
+	"illustrator": entity.AuthorRoleIllustrator,
+	"編":           entity.AuthorRoleEditor,
+	"編集":          entity.AuthorRoleEditor,
+	"editor":      entity.AuthorRoleEditor,
+}
+
+// 著者詳細取得サービス
+// 著者の書籍一覧に加えて、ログインしている場合はそれらの書籍に対するログインユーザのレビューを返す
+func (s Service) GetAuthor(c *gin.Context) (entity.AuthorResponse, StatusCode, error) {
+	db := db.GetDB()
+	var author Author
+
+	// Authorizationヘッダが指定されていない場合はレビューを返却しないため、それの判定を行う
+	var isAuthenticated bool
+	var user User
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader == "" {
+		isAuthenticated = false
+	} else {
+		// JWTトークン検証
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		token, statusCode, err := s.VerifyToken(tokenString)
+		if err != nil {
+			return entity.AuthorResponse{}, statusCode, err
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+
+		if !ok || !token.Valid {
+			return entity.AuthorResponse{}, http.StatusForbidden, err
+		}
+
+		// メールアドレスをキーに、ユーザを取得
+		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+			return entity.AuthorResponse{}, http.StatusNotFound, err
+		}
+
+		isAuthenticated = true
+	}
+
+	// IDをキーに、著者を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&author).Error; err != nil {
+		return entity.AuthorResponse{}, http.StatusNotFound, err
+	}
+
+	// 別名の場合は、正式な著者を取得
+	if author.CanonicalID != 0 {
+		if err := db.Where("id = ?", author.CanonicalID).First(&author).Error; err != nil {
+			return entity.AuthorResponse{}, http.StatusNotFound, err
+		}
+	}
+
+	response := entity.AuthorResponse{
+		ID:      author.ID,
+		Name:    author.Name,
+		Aliases: []string{},
+		Books:   []entity.AuthorBook{},
+		Reviews: []entity.ResponseReview{},
+	}
+
+	// 著者の別名を取得
+	var aliases []Author
+	if err := db.Where("canonical_id = ?", author.ID).Order("name").Find(&aliases).Error; err != nil {
+		return entity.AuthorResponse{}, http.StatusInternalServerError, err
+	}
+	authorIDs := []uint{author.ID}
+	for _, alias := range aliases {
+		response.Aliases = append(response.Aliases, alias.Name)
+		authorIDs = append(authorIDs, alias.ID)
+	}
+
+	// 著者ID(別名を含む)をキーに、書籍を取得
+	if err := db.Model(&Book{}).Select("books.id, books.title, books.author, books.thumbnail_link, books.published_date, books.num_of_pages, book_authors.role").Joins("join book_authors on book_authors.book_id = books.id").Where("book_authors.author_id IN ?", authorIDs).Order("books.published_date desc, books.id").Scan(&response.Books).Error; err != nil {
+		// SELECT books.id, books.title, books.author, books.thumbnail_link,
+		//   books.published_date, books.num_of_pages, book_authors.role
+		// FROM books join book_authors on book_authors.book_id = books.id
+		// WHERE book_authors.author_id IN ([著者ID、別名の著者ID])
+		// ORDER BY books.published_date desc, books.id
+		return entity.AuthorResponse{}, http.StatusInternalServerError, err
+	}
+
+	// ログインユーザの、著者の書籍に対するレビューを取得
+	if isAuthenticated {
+		if err := db.Model(&Review{}).Select(responseReviewColumns).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.book_id IN (?)", user.ID, db.Model(&entity.BookAuthor{}).Select("book_id").Where("author_id IN ?", authorIDs)).Order("reviews.updated_at desc").Scan(&response.Reviews).Error; err != nil {
+			// SELECT [responseReviewColumns]
+			// FROM reviews join books on reviews.book_id = books.id
+			// WHERE reviews.user_id = user.ID
+			//   AND reviews.book_id IN (SELECT book_id FROM book_authors WHERE author_id IN ([著者ID、別名の著者ID]))
+			// ORDER BY reviews.updated_at DESC
+			return entity.AuthorResponse{}, http.StatusInternalServerError, err
+		}
+		// 読書開始日、完了日が0001-01-01の場合は空文字を格納する
+		for i := range response.Reviews {
+			response.Reviews[i].StartReadAt = timeStrCoalesce(response.Reviews[i].StartReadAt, "")
+			response.Reviews[i].FinishReadAt = timeStrCoalesce(response.Reviews[i].FinishReadAt, "")
+		}
+	}
+
+	return response, http.StatusOK, nil
+}
+
+// 著者の別名登録サービス
+// 指定した名前の著者(未登録の場合は新規登録する)を、著者の別名とする(例: "村上春樹"の別名として"Haruki Murakami"を登録)
+// 別名は、書籍の重複検出で同じ著者とみなすために用いる
+func (s Service) AddAuthorAlias(c *gin.Context) (entity.ResponseAuthorAlias, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var author Author
+	var request AddAuthorAliasRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ResponseAuthorAlias{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ResponseAuthorAlias{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.ResponseAuthorAlias{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.ResponseAuthorAlias{}, http.StatusBadRequest, err
+	}
+	request.Name = strings.TrimSpace(request.Name)
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ResponseAuthorAlias{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return entity.ResponseAuthorAlias{}, http.StatusForbidden, errors.New("only moderators can add author aliases")
+	}
+
+	// IDをキーに、著者を取得(別名の場合は正式な著者)
+	if err := db.Where("id = ?", c.Param("id")).First(&author).Error; err != nil {
+		return entity.ResponseAuthorAlias{}, http.StatusNotFound, err
+	}
+	if author.CanonicalID != 0 {
+		if err := db.Where("id = ?", author.CanonicalID).First(&author).Error; err != nil {
+			return entity.ResponseAuthorAlias{}, http.StatusNotFound, err
+		}
+	}
+
+	var alias Author
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 著者名をキーに、著者を取得(無い場合は新規登録)
+		if err := tx.Where(Author{Name: request.Name}).FirstOrCreate(&alias).Error; err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		if alias.ID == author.ID {
+			statusCode = http.StatusBadRequest
+			return errors.New("an author cannot be an alias of itself")
+		}
+
+		// 別名とする著者の別名も、正式な著者の別名に付け替える
+		if err := tx.Model(&Author{}).Where("canonical_id = ?", alias.ID).Update("canonical_id", author.ID).Error; err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		alias.CanonicalID = author.ID
+		if err := tx.Model(&alias).Update("canonical_id", author.ID).Error; err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.ResponseAuthorAlias{}, statusCode, err
+	}
+
+	return entity.ResponseAuthorAlias{ID: alias.ID, Name: alias.Name, CanonicalID: alias.CanonicalID}, http.StatusCreated, nil
+}
+
+// 著者が登録されていない書籍について、カンマ区切りの著者文字列から著者を登録する
+// (著者をエンティティとして管理する以前に登録された書籍の移行用)
+func MigrateBookAuthors() error {
+	db := db.GetDB()
+	var books []Book
+	if err := db.Where("NOT EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id)").Find(&books).Error; err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return linkBookAuthors(tx, book.ID, parseContributors(book.Author))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(books) > 0 {
+		log.Printf("migrated authors of %d books", len(books))
+	}
+	return nil
+}
+
+// カンマ区切りの著者文字列を、著者と役割の組に分割する
+// 例: "山田太郎, 鈴木花子(訳)" → [{山田太郎 author} {鈴木花子 translator}]
+func parseContributors(author string) []entity.ContributorRequest {
+	var contributors []entity.ContributorRequest
+	for _, name := range strings.FieldsFunc(author, func(r rune) bool { return r == ',' || r == '、' || r == '，' }) {
+		contributor := entity.ContributorRequest{Name: strings.TrimSpace(name), Role: entity.AuthorRoleAuthor}
+
+		// 末尾の括弧書きから役割を判定する
+		normalized := strings.NewReplacer("（", "(", "）", ")").Replace(contributor.Name)
+		if open := strings.LastIndex(normalized, "("); open > 0 && strings.HasSuffix(normalized, ")") {
+			suffix := strings.ToLower(strings.TrimSpace(normalized[open+1 : len(normalized)-1]))
+			if role, ok := contributorRoleSuffixes[suffix]; ok {
+				contributor.Name = strings.TrimSpace(normalized[:open])
+				contributor.Role = role
+			}
+		}
+
+		if contributor.Name != "" {
+			contributors = append(contributors, contributor)
+		}
+	}
+	return contributors
+}
+
+// 書籍に著者を関連付ける(未登録の著者は新規登録する)
+func linkBookAuthors(tx *gorm.DB, bookID uint, contributors []entity.ContributorRequest) error {
+	for i, contributor := range contributors {
+		if contributor.Role == "" {
+			contributor.Role = entity.AuthorRoleAuthor
+		}
+
+		// 著者名をキーに、著者を取得(無い場合は新規登録)
+		var author Author
+		if err := tx.Where(Author{Name: contributor.Name}).FirstOrCreate(&author).Error; err != nil {
+			return err
+		}
+
+		bookAuthor := entity.BookAuthor{
+			BookID:   bookID,
+			AuthorID: author.ID,
+			Role:     contributor.Role,
+			Position: uint(i),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookAuthor).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 書籍の著者を取得する
+func getBookContributors(tx *gorm.DB, bookID uint) ([]entity.ResponseContributor, error) {
+	contributors := []entity.ResponseContributor{}
+	if err := tx.Model(&entity.BookAuthor{}).Select("authors.id, authors.name, book_authors.role").Joins("join authors on authors.id = book_authors.author_id").Where("book_authors.book_id = ?", bookID).Order("book_authors.position").Scan(&contributors).Error; err != nil {
+		// SELECT authors.id, authors.name, book_authors.role
+		// FROM book_authors join authors on authors.id = book_authors.author_id
+		// WHERE book_authors.book_id = [書籍ID]
+		// ORDER BY book_authors.position
+		return nil, err
+	}
+	return contributors, nil
+}
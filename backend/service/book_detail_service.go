@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 書籍詳細で返す人気のタグの件数
+const popularTagsLimit = 10
+
+// 書籍詳細取得サービス
+// 書籍データに加えて、全ユーザの公開レビューの集計と、ログインユーザ自身のレビューを返す
+func (s Service) GetBook(c *gin.Context) (entity.BookDetailResponse, StatusCode, error) {
+	db := db.GetDB()
+	var book Book
+
+	// Authorizationヘッダが指定されていない場合は自身のレビューを返却しないため、それの判定を行う
+	var isAuthenticated bool
+	var user User
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader == "" {
+		isAuthenticated = false
+	} else {
+		// JWTトークン検証
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		token, statusCode, err := s.VerifyToken(tokenString)
+		if err != nil {
+			return entity.BookDetailResponse{}, statusCode, err
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+
+		if !ok || !token.Valid {
+			return entity.BookDetailResponse{}, http.StatusForbidden, err
+		}
+
+		// メールアドレスをキーに、ユーザを取得
+		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+			return entity.BookDetailResponse{}, http.StatusNotFound, err
+		}
+
+		isAuthenticated = true
+	}
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return entity.BookDetailResponse{}, http.StatusNotFound, err
+	}
+
+	response := toBookDetailResponse(book)
+
+	// 書籍の著者を取得
+	contributors, err := getBookContributors(db, book.ID)
+	if err != nil {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+	response.Contributors = contributors
+
+	// 書籍のジャンルを取得
+	genres, err := getBookGenres(db, book.ID)
+	if err != nil {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+	response.Genres = genres
+
+	// 同じ作品の全ての版について、全ユーザの公開レビューを集計
+	community, err := getWorkCommunityStats(db, book.WorkID)
+	if err != nil {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+	response.Community = community
+
+	// ログインユーザ自身の、同じ作品(いずれかの版)に対するレビューを取得(公開範囲に関わらず返す)
+	// この版に対するレビューがある場合は、そちらを優先する
+	if isAuthenticated {
+		var myReview entity.ResponseReview
+		err := db.Model(&Review{}).Select(responseReviewColumns).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND (reviews.book_id = ? OR books.work_id = ?)", user.ID, book.ID, book.WorkID).Order(clause.OrderBy{Expression: clause.Expr{SQL: "reviews.book_id = ? DESC", Vars: []interface{}{book.ID}, WithoutParentheses: true}}).Take(&myReview).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+		}
+		if err == nil {
+			// 読書開始日、完了日が0001-01-01の場合は空文字を格納する
+			myReview.StartReadAt = timeStrCoalesce(myReview.StartReadAt, "")
+			myReview.FinishReadAt = timeStrCoalesce(myReview.FinishReadAt, "")
+			response.MyReview = &myReview
+		}
+	}
+
+	return response, http.StatusOK, nil
+}
+
+// 書籍データから、書籍詳細レスポンス用構造体を生成する(著者、ジャンル、集計等は含まない)
+func toBookDetailResponse(book Book) entity.BookDetailResponse {
+	return entity.BookDetailResponse{
+		ID:            book.ID,
+		Title:         book.Title,
+		Author:        book.Author,
+		ThumbnailLink: book.ThumbnailLink,
+		CoverLink:     bookCoverLink(book.ID),
+		PublishedDate: book.PublishedDate,
+		NumOfPages:    book.NumOfPages,
+		ISBN10:        book.ISBN10,
+		ISBN13:        book.ISBN13,
+		Provider:      book.Provider,
+		VolumeID:      book.VolumeID,
+		WorkID:        book.WorkID,
+		Description:   book.Description,
+		Publisher:     book.Publisher,
+		Language:      book.Language,
+		Categories:    splitTags(book.Categories),
+	}
+}
+
+// 全ユーザの公開レビューを集計する(publicReviewsは、集計対象の公開レビューを絞り込んだクエリを返す)
+func getCommunityStats(publicReviews func() *gorm.DB) (entity.BookCommunityStats, error) {
+	var stats entity.BookCommunityStats
+
+	// 平均評価と評価件数(未評価のレビューは除く)
+	var rating struct {
+		AverageRating float64
+		NumOfRatings  int64
+	}
+	if err := publicReviews().Select("coalesce(avg(rating), 0) as average_rating, count(*) as num_of_ratings").Where("rating > 0").Scan(&rating).Error; err != nil {
+		// SELECT coalesce(avg(rating), 0) as average_rating, count(*) as num_of_ratings
+		// FROM reviews
+		// WHERE [集計対象の公開レビュー] AND rating > 0
+		return stats, err
+	}
+	stats.AverageRating = rating.AverageRating
+	stats.NumOfRatings = rating.NumOfRatings
+
+	// 評価ごとの件数(0.5刻み、件数が0の評価も含める)
+	var ratingCounts []entity.RatingCount
+	if err := publicReviews().Select("rating, count(*) as count").Where("rating > 0").Group("rating").Scan(&ratingCounts).Error; err != nil {
+		// SELECT rating, count(*) as count
+		// FROM reviews
+		// WHERE [集計対象の公開レビュー] AND rating > 0
+		// GROUP BY rating
+		return stats, err
+	}
+	stats.RatingHistogram = []entity.RatingCount{}
+	for rating := 0.5; rating <= 5.0; rating += 0.5 {
+		histogram := entity.RatingCount{Rating: rating}
+		for _, ratingCount := range ratingCounts {
+			if ratingCount.Rating == rating {
+				histogram.Count = ratingCount.Count
+			}
+		}
+		stats.RatingHistogram = append(stats.RatingHistogram, histogram)
+	}
+
+	// 読了したユーザ数と、読書中のユーザ数(作品単位では複数の版をレビューしたユーザがいるため、ユーザ単位で数える)
+	if err := publicReviews().Where("reading_status = ?", entity.ReadingStatusFinish).Distinct("reviews.user_id").Count(&stats.NumOfReaders).Error; err != nil {
+		// SELECT count(distinct reviews.user_id)
+		// FROM reviews
+		// WHERE [集計対象の公開レビュー] AND reading_status = 'Finish'
+		return stats, err
+	}
+	if err := publicReviews().Where("reading_status = ?", entity.ReadingStatusReading).Distinct("reviews.user_id").Count(&stats.NumOfReading).Error; err != nil {
+		return stats, err
+	}
+
+	// 人気のタグ(カンマ区切りのタグを分割して集計)
+	stats.PopularTags = []entity.TagCount{}
+	if err := publicReviews().Select("trim(tag) as tag, count(*) as count").Joins("cross join unnest(string_to_array(reviews.tags, ',')) as tag").Where("trim(tag) <> ''").Group("trim(tag)").Order("count desc, tag").Limit(popularTagsLimit).Scan(&stats.PopularTags).Error; err != nil {
+		// SELECT trim(tag) as tag, count(*) as count
+		// FROM reviews cross join unnest(string_to_array(reviews.tags, ',')) as tag
+		// WHERE [集計対象の公開レビュー] AND trim(tag) <> ''
+		// GROUP BY trim(tag)
+		// ORDER BY count desc, tag
+		// LIMIT 10
+		return stats, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,699 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookEdit entity.BookEdit
+type BookChange entity.BookChange
+type CreateBookRequest entity.CreateBookRequest
+type SuggestBookEditRequest entity.SuggestBookEditRequest
+type ModerateBookEditRequest entity.ModerateBookEditRequest
+
+// 手動で登録した書籍のプロバイダ名
+const manualBookProvider = "manual"
+
+// 変更履歴に記録する書籍データの項目
+type bookField struct {
+	Name   string // レスポンスのJSONのキー
+	Column string
+	Value  func(Book) string
+}
+
+var bookFields = []bookField{
+	{Name: "title", Column: "title", Value: func(b Book) string { return b.Title }},
+	{Name: "author", Column: "author", Value: func(b Book) string { return b.Author }},
+	{Name: "thumbnailLink", Column: "thumbnail_link", Value: func(b Book) string { return b.ThumbnailLink }},
+	{Name: "publishedDate", Column: "published_date", Value: func(b Book) string { return b.PublishedDate }},
+	{Name: "numOfPages", Column: "num_of_pages", Value: func(b Book) string { return formatNumOfPages(b.NumOfPages) }},
+	{Name: "isbn10", Column: "isbn10", Value: func(b Book) string { return b.ISBN10 }},
+	{Name: "isbn13", Column: "isbn13", Value: func(b Book) string { return b.ISBN13 }},
+	{Name: "categories", Column: "categories", Value: func(b Book) string { return b.Categories }},
+	{Name: "description", Column: "description", Value: func(b Book) string { return b.Description }},
+	{Name: "publisher", Column: "publisher", Value: func(b Book) string { return b.Publisher }},
+	{Name: "language", Column: "language", Value: func(b Book) string { return b.Language }},
+}
+
+// 書籍の出版日として受け付ける形式(書籍メタデータプロバイダの形式に合わせる)
+var publishedDateLayouts = []string{"2006", "2006-01", "2006-01-02"}
+
+// 書籍登録サービス
+// 書籍メタデータプロバイダに無い書籍(自費出版の書籍等)を手動で登録する
+func (s Service) CreateBook(c *gin.Context) (entity.BookDetailResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request CreateBookRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.BookDetailResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.BookDetailResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.BookDetailResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.BookDetailResponse{}, http.StatusBadRequest, err
+	}
+	request.Title = strings.TrimSpace(request.Title)
+	request.Author = strings.TrimSpace(request.Author)
+	if request.Title == "" || request.Author == "" {
+		return entity.BookDetailResponse{}, http.StatusBadRequest, errors.New("title and author must not be blank")
+	}
+	if !isValidPublishedDate(request.PublishedDate) {
+		return entity.BookDetailResponse{}, http.StatusBadRequest, errors.New("publishedDate must be in the format YYYY, YYYY-MM or YYYY-MM-DD")
+	}
+	identity := bookIdentity{Title: request.Title, Author: request.Author}
+	if request.ISBN != "" {
+		identity.ISBN10, identity.ISBN13, err = normalizeISBN(request.ISBN)
+		if err != nil {
+			return entity.BookDetailResponse{}, http.StatusBadRequest, err
+		}
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.BookDetailResponse{}, http.StatusNotFound, err
+	}
+
+	// 登録済みの書籍と重複する場合は登録しない
+	if book, err := findBook(db, identity); err == nil {
+		return entity.BookDetailResponse{}, http.StatusConflict, fmt.Errorf("book already exists (id: %d)", book.ID)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+
+	// 作品IDが指定されている場合は、作品が存在するか確認
+	if request.WorkID != 0 {
+		if err := db.First(&Work{}, request.WorkID).Error; err != nil {
+			return entity.BookDetailResponse{}, http.StatusNotFound, err
+		}
+	}
+
+	// Bookを新規作成(著者、ジャンル、作品の関連付けも合わせて行う)
+	book := Book{
+		Title:         request.Title,
+		Author:        request.Author,
+		ThumbnailLink: request.ThumbnailLink,
+		PublishedDate: request.PublishedDate,
+		NumOfPages:    request.NumOfPages,
+		ISBN10:        identity.ISBN10,
+		ISBN13:        identity.ISBN13,
+		Provider:      manualBookProvider,
+		Categories:    strings.Join(request.Categories, ","),
+		Description:   request.Description,
+		Publisher:     request.Publisher,
+		Language:      request.Language,
+	}
+	// 同時に同じ書籍が登録された場合は、登録済みとする
+	existed, err := createOrFindBook(db, &book, request.Contributors, request.WorkID, user.ID)
+	if err != nil {
+		return entity.BookDetailResponse{}, http.StatusBadRequest, err
+	}
+	if existed {
+		return entity.BookDetailResponse{}, http.StatusConflict, fmt.Errorf("book already exists (id: %d)", book.ID)
+	}
+
+	// レスポンス用データ生成
+	response := toBookDetailResponse(book)
+	if response.Contributors, err = getBookContributors(db, book.ID); err != nil {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+	if response.Genres, err = getBookGenres(db, book.ID); err != nil {
+		return entity.BookDetailResponse{}, http.StatusInternalServerError, err
+	}
+	response.Community = entity.BookCommunityStats{RatingHistogram: []entity.RatingCount{}, PopularTags: []entity.TagCount{}}
+
+	return response, http.StatusCreated, nil
+}
+
+// 書籍データの修正提案サービス
+// 提案はモデレータが承認するまで書籍データに反映しない
+func (s Service) SuggestBookEdit(c *gin.Context) (entity.ResponseBookEdit, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var book Book
+	var request SuggestBookEditRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ResponseBookEdit{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ResponseBookEdit{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.ResponseBookEdit{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.ResponseBookEdit{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ResponseBookEdit{}, http.StatusNotFound, err
+	}
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return entity.ResponseBookEdit{}, http.StatusNotFound, err
+	}
+
+	edit := BookEdit{
+		Title:         trimSpacePtr(request.Title),
+		Author:        trimSpacePtr(request.Author),
+		ThumbnailLink: request.ThumbnailLink,
+		PublishedDate: request.PublishedDate,
+		NumOfPages:    request.NumOfPages,
+		ISBN:          request.ISBN,
+		Publisher:     request.Publisher,
+		Language:      request.Language,
+		Description:   request.Description,
+		Comment:       request.Comment,
+		Status:        entity.BookEditStatusPending,
+		BookID:        book.ID,
+		UserID:        user.ID,
+	}
+
+	// 修正後の書籍データを検証し、現在の書籍データから変更が無い場合は登録しない
+	edited, err := applyBookEdit(book, edit)
+	if err != nil {
+		return entity.ResponseBookEdit{}, http.StatusBadRequest, err
+	}
+	changes := diffBookFields(book, edited)
+	if len(changes) == 0 {
+		return entity.ResponseBookEdit{}, http.StatusBadRequest, errors.New("no changes were suggested")
+	}
+
+	if err := db.Create(&edit).Error; err != nil {
+		return entity.ResponseBookEdit{}, http.StatusBadRequest, err
+	}
+
+	return toResponseBookEdit(edit, book, user.Name, changes), http.StatusCreated, nil
+}
+
+// 書籍データの修正提案一覧取得サービス
+// 書籍ごとの修正提案を、新しい順に返す
+func (s Service) GetBookEdits(c *gin.Context) ([]entity.ResponseBookEdit, StatusCode, error) {
+	db := db.GetDB()
+	var book Book
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return []entity.ResponseBookEdit{}, http.StatusNotFound, err
+	}
+
+	query := db.Where("book_edits.book_id = ?", book.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("book_edits.status = ?", status)
+	}
+	edits, err := getBookEdits(query, "book_edits.id desc")
+	if err != nil {
+		return []entity.ResponseBookEdit{}, http.StatusInternalServerError, err
+	}
+	return edits, http.StatusOK, nil
+}
+
+// モデレータ用の書籍データの修正提案一覧取得サービス
+// 全書籍の修正提案を古い順に返す(ステータスが指定されない場合は保留中の提案のみ)
+func (s Service) GetBookEditQueue(c *gin.Context) ([]entity.ResponseBookEdit, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.ResponseBookEdit{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.ResponseBookEdit{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.ResponseBookEdit{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return []entity.ResponseBookEdit{}, http.StatusForbidden, errors.New("only moderators can moderate book edits")
+	}
+
+	status := c.DefaultQuery("status", entity.BookEditStatusPending)
+	edits, err := getBookEdits(db.Where("book_edits.status = ?", status), "book_edits.id")
+	if err != nil {
+		return []entity.ResponseBookEdit{}, http.StatusInternalServerError, err
+	}
+	return edits, http.StatusOK, nil
+}
+
+// 書籍データの修正提案承認サービス
+// 提案された修正を書籍データに反映し、変更履歴を記録する
+func (s Service) ApproveBookEdit(c *gin.Context) (entity.ResponseBookEdit, StatusCode, error) {
+	return s.moderateBookEdit(c, entity.BookEditStatusApproved)
+}
+
+// 書籍データの修正提案却下サービス
+func (s Service) RejectBookEdit(c *gin.Context) (entity.ResponseBookEdit, StatusCode, error) {
+	return s.moderateBookEdit(c, entity.BookEditStatusRejected)
+}
+
+// 書籍データの変更履歴取得サービス
+// 書籍の登録、修正提案の承認による変更を、新しい順に返す
+func (s Service) GetBookHistory(c *gin.Context) ([]entity.ResponseBookChange, StatusCode, error) {
+	db := db.GetDB()
+	var book Book
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return []entity.ResponseBookChange{}, http.StatusNotFound, err
+	}
+
+	changes := []entity.ResponseBookChange{}
+	err := db.Model(&BookChange{}).
+		Select("book_changes.id, book_changes.action, book_changes.field, book_changes.old_value, book_changes.new_value, book_changes.book_edit_id, book_changes.created_at, coalesce(users.name, '') as user_name, coalesce(moderators.name, '') as moderator_name").
+		Joins("left join users on users.id = book_changes.user_id").
+		Joins("left join users moderators on moderators.id = book_changes.moderator_id").
+		Where("book_changes.book_id = ?", book.ID).
+		Order("book_changes.id desc").
+		Scan(&changes).Error
+	if err != nil {
+		// SELECT book_changes.id, book_changes.action, book_changes.field, book_changes.old_value, book_changes.new_value,
+		//   book_changes.book_edit_id, book_changes.created_at, coalesce(users.name, '') as user_name, coalesce(moderators.name, '') as moderator_name
+		// FROM book_changes
+		// left join users on users.id = book_changes.user_id
+		// left join users moderators on moderators.id = book_changes.moderator_id
+		// WHERE book_changes.book_id = [書籍ID]
+		// ORDER BY book_changes.id desc
+		return []entity.ResponseBookChange{}, http.StatusInternalServerError, err
+	}
+	return changes, http.StatusOK, nil
+}
+
+// 環境変数MODERATOR_EMAILS(カンマ区切り)に指定されたメールアドレスのユーザを、モデレータにする
+// 指定が無いユーザのモデレータ権限は変更しない
+func MigrateModerators() error {
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("MODERATOR_EMAILS"), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	db := db.GetDB()
+	return db.Model(&User{}).Where("email IN ?", emails).Update("is_moderator", true).Error
+}
+
+// 書籍データの修正提案を承認、または却下する
+func (s Service) moderateBookEdit(c *gin.Context, status string) (entity.ResponseBookEdit, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request ModerateBookEditRequest
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ResponseBookEdit{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ResponseBookEdit{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得(コメントは任意のため、本文が無い場合は空とする)
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&request); err != nil {
+			return entity.ResponseBookEdit{}, http.StatusBadRequest, err
+		}
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ResponseBookEdit{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return entity.ResponseBookEdit{}, http.StatusForbidden, errors.New("only moderators can moderate book edits")
+	}
+
+	var edit BookEdit
+	var book Book
+	var changes []entity.ResponseBookField
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 同時に承認、却下されないよう、修正提案をロックして取得
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", c.Param("editId")).First(&edit).Error; err != nil {
+			statusCode = http.StatusNotFound
+			return err
+		}
+		if edit.Status != entity.BookEditStatusPending {
+			statusCode = http.StatusConflict
+			return fmt.Errorf("this edit has already been %s", edit.Status)
+		}
+		if err := tx.Where("id = ?", edit.BookID).First(&book).Error; err != nil {
+			statusCode = http.StatusNotFound
+			return err
+		}
+
+		if status == entity.BookEditStatusApproved {
+			edited, err := applyBookEdit(book, edit)
+			if err != nil {
+				statusCode = http.StatusBadRequest
+				return err
+			}
+			changes = diffBookFields(book, edited)
+			if statusCode, err = updateBook(tx, book, edited, changes, edit, user.ID); err != nil {
+				return err
+			}
+			book = edited
+		}
+
+		edit.Status = status
+		edit.ModeratorID = user.ID
+		edit.ModeratorComment = request.Comment
+		edit.ModeratedAt = time.Now().Unix()
+		if err := tx.Model(&edit).Select("status", "moderator_id", "moderator_comment", "moderated_at").Updates(&edit).Error; err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.ResponseBookEdit{}, statusCode, err
+	}
+
+	// レスポンス用データ生成(却下した場合は、提案された変更を返す)
+	if status == entity.BookEditStatusRejected {
+		edited, _ := applyBookEdit(book, edit)
+		changes = diffBookFields(book, edited)
+	}
+	var proposer User
+	db.Where("id = ?", edit.UserID).First(&proposer)
+	return toResponseBookEdit(edit, book, proposer.Name, changes), http.StatusOK, nil
+}
+
+// 修正後の書籍データで書籍を更新し、変更履歴を記録する
+// 著者が変更された場合は、著者の関連付けをやり直す
+func updateBook(tx *gorm.DB, book, edited Book, changes []entity.ResponseBookField, edit BookEdit, moderatorID uint) (StatusCode, error) {
+	if len(changes) == 0 {
+		return http.StatusOK, nil
+	}
+
+	// ISBNが他の書籍と重複する場合は反映しない
+	if edited.ISBN13 != "" && edited.ISBN13 != book.ISBN13 {
+		var count int64
+		if err := tx.Model(&Book{}).Where("isbn13 = ? AND id <> ?", edited.ISBN13, book.ID).Count(&count).Error; err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if count > 0 {
+			return http.StatusConflict, errors.New("another book already has this ISBN")
+		}
+	}
+
+	columns := []string{"updated_at"}
+	for _, change := range changes {
+		columns = append(columns, bookFieldByName(change.Field).Column)
+	}
+	if err := tx.Model(&edited).Select(columns).Updates(&edited).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if edited.Author != book.Author {
+		if err := tx.Where("book_id = ?", book.ID).Delete(&entity.BookAuthor{}).Error; err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if err := linkBookAuthors(tx, book.ID, parseContributors(edited.Author)); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	// ページ数が変更された場合は、この書籍を読了したユーザの統計を更新
+	if edited.NumOfPages != book.NumOfPages {
+		if err := refreshBookReviewStats(tx, book.ID); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	for _, change := range changes {
+		bookChange := BookChange{
+			Action:      entity.BookChangeActionEdit,
+			Field:       change.Field,
+			OldValue:    change.OldValue,
+			NewValue:    change.NewValue,
+			UserID:      edit.UserID,
+			ModeratorID: moderatorID,
+			BookEditID:  edit.ID,
+			BookID:      book.ID,
+		}
+		if err := tx.Create(&bookChange).Error; err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// 書籍の登録を変更履歴に記録する(値のある項目ごとに記録する)
+func recordBookCreation(tx *gorm.DB, book Book, userID uint) error {
+	for _, change := range diffBookFields(Book{}, book) {
+		bookChange := BookChange{
+			Action:   entity.BookChangeActionCreate,
+			Field:    change.Field,
+			NewValue: change.NewValue,
+			UserID:   userID,
+			BookID:   book.ID,
+		}
+		if err := tx.Create(&bookChange).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 書籍データに修正提案を適用した書籍データを返す(修正後の値を検証する)
+func applyBookEdit(book Book, edit BookEdit) (Book, error) {
+	if edit.Title != nil {
+		if *edit.Title == "" {
+			return Book{}, errors.New("title must not be blank")
+		}
+		book.Title = *edit.Title
+	}
+	if edit.Author != nil {
+		if *edit.Author == "" {
+			return Book{}, errors.New("author must not be blank")
+		}
+		book.Author = *edit.Author
+	}
+	if edit.ThumbnailLink != nil {
+		book.ThumbnailLink = *edit.ThumbnailLink
+	}
+	if edit.PublishedDate != nil {
+		if !isValidPublishedDate(*edit.PublishedDate) {
+			return Book{}, errors.New("publishedDate must be in the format YYYY, YYYY-MM or YYYY-MM-DD")
+		}
+		book.PublishedDate = *edit.PublishedDate
+	}
+	if edit.NumOfPages != nil {
+		book.NumOfPages = *edit.NumOfPages
+	}
+	if edit.ISBN != nil {
+		book.ISBN10, book.ISBN13 = "", ""
+		if *edit.ISBN != "" {
+			var err error
+			if book.ISBN10, book.ISBN13, err = normalizeISBN(*edit.ISBN); err != nil {
+				return Book{}, err
+			}
+		}
+	}
+	if edit.Publisher != nil {
+		book.Publisher = *edit.Publisher
+	}
+	if edit.Language != nil {
+		book.Language = *edit.Language
+	}
+	if edit.Description != nil {
+		book.Description = *edit.Description
+	}
+	return book, nil
+}
+
+// 2つの書籍データで、値が異なる項目を返す
+func diffBookFields(before, after Book) []entity.ResponseBookField {
+	changes := []entity.ResponseBookField{}
+	for _, field := range bookFields {
+		oldValue, newValue := field.Value(before), field.Value(after)
+		if oldValue != newValue {
+			changes = append(changes, entity.ResponseBookField{Field: field.Name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
+
+// 項目名から、変更履歴に記録する書籍データの項目を返す
+func bookFieldByName(name string) bookField {
+	for _, field := range bookFields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return bookField{}
+}
+
+// 書籍データの修正提案を、提案したユーザ名とともに取得する
+// 保留中、却下済みの提案は現在の書籍データとの差分を、承認済みの提案は変更履歴を返す
+func getBookEdits(query *gorm.DB, order string) ([]entity.ResponseBookEdit, error) {
+	var rows []struct {
+		BookEdit
+		UserName string
+	}
+	err := query.Model(&BookEdit{}).Select("book_edits.*, users.name as user_name").
+		Joins("join users on users.id = book_edits.user_id").
+		Order(order).Scan(&rows).Error
+	if err != nil {
+		// SELECT book_edits.*, users.name as user_name
+		// FROM book_edits join users on users.id = book_edits.user_id
+		// WHERE [絞り込み条件]
+		// ORDER BY [並び順]
+		return nil, err
+	}
+
+	// 修正提案の対象の書籍と、承認済みの提案の変更履歴を取得
+	var bookIDs, approvedIDs []uint
+	for _, row := range rows {
+		bookIDs = append(bookIDs, row.BookID)
+		if row.Status == entity.BookEditStatusApproved {
+			approvedIDs = append(approvedIDs, row.ID)
+		}
+	}
+	books := map[uint]Book{}
+	if len(bookIDs) > 0 {
+		var bookList []Book
+		if err := db.GetDB().Where("id IN ?", bookIDs).Find(&bookList).Error; err != nil {
+			return nil, err
+		}
+		for _, book := range bookList {
+			books[book.ID] = book
+		}
+	}
+	changesByEdit := map[uint][]entity.ResponseBookField{}
+	if len(approvedIDs) > 0 {
+		var bookChanges []BookChange
+		if err := db.GetDB().Where("book_edit_id IN ?", approvedIDs).Order("id").Find(&bookChanges).Error; err != nil {
+			return nil, err
+		}
+		for _, change := range bookChanges {
+			changesByEdit[change.BookEditID] = append(changesByEdit[change.BookEditID], entity.ResponseBookField{Field: change.Field, OldValue: change.OldValue, NewValue: change.NewValue})
+		}
+	}
+
+	edits := []entity.ResponseBookEdit{}
+	for _, row := range rows {
+		book := books[row.BookID]
+		changes, ok := changesByEdit[row.ID]
+		if !ok {
+			changes = []entity.ResponseBookField{}
+		}
+		if row.Status != entity.BookEditStatusApproved {
+			// 検証済みの提案のため、エラーは起こらない(書籍が変更されている場合は差分が無いこともある)
+			edited, _ := applyBookEdit(book, row.BookEdit)
+			changes = diffBookFields(book, edited)
+		}
+		edits = append(edits, toResponseBookEdit(row.BookEdit, book, row.UserName, changes))
+	}
+	return edits, nil
+}
+
+// レスポンス用の修正提案を生成する
+func toResponseBookEdit(edit BookEdit, book Book, userName string, changes []entity.ResponseBookField) entity.ResponseBookEdit {
+	return entity.ResponseBookEdit{
+		ID:               edit.ID,
+		BookID:           edit.BookID,
+		BookTitle:        book.Title,
+		UserName:         userName,
+		Changes:          changes,
+		Comment:          edit.Comment,
+		Status:           edit.Status,
+		ModeratorComment: edit.ModeratorComment,
+		ModeratedAt:      edit.ModeratedAt,
+		CreatedAt:        edit.CreatedAt,
+	}
+}
+
+// ページ数を文字列にする(0は未登録とみなし空文字とする)
+func formatNumOfPages(numOfPages uint) string {
+	if numOfPages == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(numOfPages), 10)
+}
+
+// 書籍の出版日の形式か判定する(空文字は未登録とみなし許可する)
+func isValidPublishedDate(date string) bool {
+	if date == "" {
+		return true
+	}
+	for _, layout := range publishedDateLayouts {
+		if _, err := time.Parse(layout, date); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// 前後の空白を取り除いた文字列のポインタを返す(nilの場合はnilを返す)
+func trimSpacePtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	return &trimmed
+}
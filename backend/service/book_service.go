@@ -1,29 +1,38 @@
 package service
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/KoyoMiyazaki/Book-Reviewer/db"
 	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/KoyoMiyazaki/Book-Reviewer/provider"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
 )
 
 type ResponseBook entity.ResponseBook
-type ResponseFromGoogleBooks entity.ResponseFromGoogleBooks
 type SearchResponse entity.SearchResponse
 
+// 書籍検索の1ページあたりの件数の既定値と上限
+const (
+	defaultSearchPageSize = 10
+	maxSearchPageSize     = 40
+)
+
 // 書籍検索サービス
-func (s Service) SearchBooks(c *gin.Context) ([]entity.ResponseBook, StatusCode, error) {
+func (s Service) SearchBooks(c *gin.Context) (SearchResponse, StatusCode, error) {
 	db := db.GetDB()
-	search := c.Query("search")
-	// searchパラメータが指定されていな場合はエラー
-	if search == "" {
-		return []entity.ResponseBook{}, http.StatusBadRequest, fmt.Errorf("search word must be 1 or more characters")
+
+	// クエリパラメータから検索条件を取得
+	query, err := searchQueryFromParams(c)
+	if err != nil {
+		return SearchResponse{}, http.StatusBadRequest, err
 	}
 
 	// Authorizationヘッダが指定されていない場合は全書籍のisReviewedをfalseとして返却するため、それの判定を行う
@@ -38,103 +47,452 @@ func (s Service) SearchBooks(c *gin.Context) ([]entity.ResponseBook, StatusCode,
 
 		token, statusCode, err := s.VerifyToken(tokenString)
 		if err != nil {
-			return []entity.ResponseBook{}, statusCode, err
+			return SearchResponse{}, statusCode, err
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 
 		if !ok || !token.Valid {
-			return []entity.ResponseBook{}, http.StatusForbidden, err
+			return SearchResponse{}, http.StatusForbidden, err
 		}
 
 		// メールアドレスをキーに、ユーザを取得
 		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
-			return []entity.ResponseBook{}, http.StatusNotFound, err
+			return SearchResponse{}, http.StatusNotFound, err
 		}
 
 		isAuthenticated = true
 	}
 
-	// searchパラメータを指定し、Google Books APIからデータ取得
-	booksFromGoogleBooks, err := getBooksFromGoogleBooks(search)
+	// noCacheパラメータ、またはCache-Control: no-cacheヘッダが指定されている場合はキャッシュを用いない
+	ctx := c.Request.Context()
+	if noCache, _ := strconv.ParseBool(c.Query("noCache")); noCache || c.GetHeader("Cache-Control") == "no-cache" {
+		ctx = provider.WithoutCache(ctx)
+	}
+
+	// 検索条件を指定し、書籍メタデータプロバイダからデータ取得
+	result, err := provider.Default().Search(ctx, query)
 	if err != nil {
-		return []entity.ResponseBook{}, http.StatusInternalServerError, err
+		return SearchResponse{}, http.StatusInternalServerError, err
 	}
 
-	// Google Books APIから受け取った書籍データを、書籍検索レスポンス用の書籍構造体へと変換
-	var response SearchResponse
-	var responseBooks []entity.ResponseBook
-	for _, bookFromGoogleBooks := range booksFromGoogleBooks.Items {
-		responseBook := entity.ResponseBook{}
-		responseBook.ID = bookFromGoogleBooks.ID
-		responseBook.Title = bookFromGoogleBooks.VolumeInfo.Title
-		// 著者が複数いる場合はカンマで区切る
-		responseBook.Author = strings.Join(bookFromGoogleBooks.VolumeInfo.Authors, ", ")
-		responseBook.ThumbnailLink = bookFromGoogleBooks.VolumeInfo.ImageLinks.Thumbnail
-		responseBook.PublishedDate = bookFromGoogleBooks.VolumeInfo.PublishedDate
-		responseBook.NumOfPages = bookFromGoogleBooks.VolumeInfo.PageCount
+	// プロバイダから受け取った書籍データを、書籍検索レスポンス用の書籍構造体へと変換
+	response := SearchResponse{
+		ResponseBooks: []entity.ResponseBook{},
+		TotalItems:    result.TotalItems,
+		TotalPages:    (result.TotalItems + query.PageSize - 1) / query.PageSize,
+		Page:          query.Page,
+		PageSize:      query.PageSize,
+	}
+	for _, providerBook := range result.Books {
+		responseBook := toResponseBook(providerBook)
 		if isAuthenticated {
 			// ログインユーザが対象の書籍をレビュー済みか判定
-			responseBook.IsReviewed = isReviewed(user.ID, responseBook.Title, responseBook.Author)
+			responseBook.IsReviewed = isReviewed(user.ID, bookIdentityFromResponseBook(responseBook))
 		} else {
 			responseBook.IsReviewed = false
 		}
-		responseBook.IsForSale = bookFromGoogleBooks.SaleInfo.IsEbook
-		responseBook.Price = bookFromGoogleBooks.SaleInfo.RetailPrice.Amount
-		responseBook.BuyLink = bookFromGoogleBooks.SaleInfo.BuyLink
 
-		responseBooks = append(responseBooks, responseBook)
+		response.ResponseBooks = append(response.ResponseBooks, responseBook)
 	}
 
-	response.ResponseBooks = responseBooks
+	return response, http.StatusOK, nil
+}
+
+// クエリパラメータから書籍検索の検索条件を生成する
+// search、title、author、isbn、publisher、subjectのうち、いずれかの指定が必要
+func searchQueryFromParams(c *gin.Context) (entity.ProviderSearchQuery, error) {
+	query := entity.ProviderSearchQuery{
+		Query:     strings.TrimSpace(c.Query("search")),
+		Title:     strings.TrimSpace(c.Query("title")),
+		Author:    strings.TrimSpace(c.Query("author")),
+		ISBN:      strings.TrimSpace(c.Query("isbn")),
+		Publisher: strings.TrimSpace(c.Query("publisher")),
+		Subject:   strings.TrimSpace(c.Query("subject")),
+		Language:  strings.TrimSpace(c.Query("lang")),
+		Page:      1,
+		PageSize:  defaultSearchPageSize,
+		OrderBy:   entity.SearchOrderRelevance,
+	}
+	// 検索ワード、検索項目のいずれも指定されていない場合はエラー
+	if query.Query == "" && query.Title == "" && query.Author == "" && query.ISBN == "" && query.Publisher == "" && query.Subject == "" {
+		return query, fmt.Errorf("search word must be 1 or more characters")
+	}
+
+	// ページング
+	if page := c.Query("page"); page != "" {
+		number, err := strconv.Atoi(page)
+		if err != nil || number < 1 {
+			return query, fmt.Errorf("page must be a positive integer")
+		}
+		query.Page = number
+	}
+	if pageSize := c.Query("pageSize"); pageSize != "" {
+		number, err := strconv.Atoi(pageSize)
+		if err != nil || number < 1 || number > maxSearchPageSize {
+			return query, fmt.Errorf("pageSize must be between 1 and %d", maxSearchPageSize)
+		}
+		query.PageSize = number
+	}
 
-	return responseBooks, http.StatusOK, nil
+	// 絞り込み
+	if ebook := c.Query("ebook"); ebook != "" {
+		value, err := strconv.ParseBool(ebook)
+		if err != nil {
+			return query, fmt.Errorf("ebook must be true or false")
+		}
+		query.EbookOnly = value
+	}
+	if forSale := c.Query("forSale"); forSale != "" {
+		value, err := strconv.ParseBool(forSale)
+		if err != nil {
+			return query, fmt.Errorf("forSale must be true or false")
+		}
+		query.ForSale = value
+	}
+
+	// 並び順
+	if orderBy := c.Query("orderBy"); orderBy != "" {
+		if orderBy != entity.SearchOrderRelevance && orderBy != entity.SearchOrderNewest {
+			return query, fmt.Errorf("orderBy must be relevance or newest")
+		}
+		query.OrderBy = orderBy
+	}
+
+	return query, nil
 }
 
-// Google Books APIからデータ取得
-func getBooksFromGoogleBooks(searchWord string) (ResponseFromGoogleBooks, error) {
-	// Google Books API宛てにGET
-	apiUrl := fmt.Sprintf("https://www.googleapis.com/books/v1/volumes?q=%s", searchWord)
-	req, err := http.NewRequest("GET", apiUrl, nil)
+// ISBNによる書籍取得サービス(バーコード読み取り用)
+// 書籍メタデータプロバイダに無い場合は、登録済みの書籍データを返す
+func (s Service) GetBookByISBN(c *gin.Context) (entity.ResponseBook, StatusCode, error) {
+	db := db.GetDB()
+
+	// ISBNを検証し、ISBN-10とISBN-13の組に正規化
+	isbn10, isbn13, err := normalizeISBN(c.Param("isbn"))
 	if err != nil {
-		return ResponseFromGoogleBooks{}, err
+		return entity.ResponseBook{}, http.StatusBadRequest, err
 	}
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		return ResponseFromGoogleBooks{}, err
+
+	// Authorizationヘッダが指定されていない場合はisReviewedをfalseとして返却するため、それの判定を行う
+	var isAuthenticated bool
+	var user User
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader == "" {
+		isAuthenticated = false
+	} else {
+		// JWTトークン検証
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		token, statusCode, err := s.VerifyToken(tokenString)
+		if err != nil {
+			return entity.ResponseBook{}, statusCode, err
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+
+		if !ok || !token.Valid {
+			return entity.ResponseBook{}, http.StatusForbidden, err
+		}
+
+		// メールアドレスをキーに、ユーザを取得
+		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+			return entity.ResponseBook{}, http.StatusNotFound, err
+		}
+
+		isAuthenticated = true
+	}
+
+	// ISBN-13をキーに、書籍メタデータプロバイダからデータ取得
+	var responseBook entity.ResponseBook
+	providerBook, err := provider.Default().LookupByISBN(c.Request.Context(), isbn13)
+	if errors.Is(err, provider.ErrNotFound) && isbn10 != "" {
+		// ISBN-13で見つからない場合は、ISBN-10で取得
+		providerBook, err = provider.Default().LookupByISBN(c.Request.Context(), isbn10)
+	}
+	switch {
+	case err == nil:
+		responseBook = toResponseBook(providerBook)
+		// プロバイダによってはISBNを返さないため、指定されたISBNで補完する
+		if responseBook.ISBN13 == "" {
+			responseBook.ISBN10, responseBook.ISBN13 = isbn10, isbn13
+		}
+	case errors.Is(err, provider.ErrNotFound):
+		// 書籍メタデータプロバイダに無い場合は、登録済みの書籍データを取得
+		book, err := findBook(db, bookIdentity{ISBN10: isbn10, ISBN13: isbn13})
+		if err != nil {
+			return entity.ResponseBook{}, http.StatusNotFound, fmt.Errorf("book with isbn %s not found", isbn13)
+		}
+		responseBook = entity.ResponseBook{
+			ID:            book.VolumeID,
+			Provider:      book.Provider,
+			Title:         book.Title,
+			Author:        book.Author,
+			ThumbnailLink: book.ThumbnailLink,
+			PublishedDate: book.PublishedDate,
+			NumOfPages:    book.NumOfPages,
+			ISBN10:        book.ISBN10,
+			ISBN13:        book.ISBN13,
+			Categories:    splitTags(book.Categories),
+			Description:   book.Description,
+			Publisher:     book.Publisher,
+			Language:      book.Language,
+		}
+	default:
+		return entity.ResponseBook{}, http.StatusInternalServerError, err
+	}
+
+	if isAuthenticated {
+		// ログインユーザが対象の書籍をレビュー済みか判定
+		responseBook.IsReviewed = isReviewed(user.ID, bookIdentityFromResponseBook(responseBook))
 	}
-	defer res.Body.Close()
 
-	// レスポンスのボディ部分を読み込み
-	body, err := ioutil.ReadAll(res.Body)
+	return responseBook, http.StatusOK, nil
+}
+
+// 書籍メタデータのキャッシュの統計情報取得サービス(モデレータのみ)
+func (s Service) GetBookCacheStats(c *gin.Context) (entity.BookCacheStats, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
 	if err != nil {
-		return ResponseFromGoogleBooks{}, err
+		return entity.BookCacheStats{}, statusCode, err
 	}
 
-	// レスポンスデータ(JSON)を、Google Books APIのレスポンス用構造体に変換
-	booksFromGoogleBooks := ResponseFromGoogleBooks{}
-	if err := json.Unmarshal(body, &booksFromGoogleBooks); err != nil {
-		return ResponseFromGoogleBooks{}, err
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.BookCacheStats{}, http.StatusForbidden, err
 	}
 
-	return booksFromGoogleBooks, nil
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.BookCacheStats{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return entity.BookCacheStats{}, http.StatusForbidden, errors.New("only moderators can view cache stats")
+	}
+
+	cache, ok := provider.Default().(*provider.Cache)
+	if !ok {
+		return entity.BookCacheStats{}, http.StatusNotFound, fmt.Errorf("book metadata cache is disabled")
+	}
+	return cache.Stats(), http.StatusOK, nil
+}
+
+// プロバイダから受け取った書籍データを、書籍検索レスポンス用の書籍構造体へと変換する
+func toResponseBook(providerBook entity.ProviderBook) entity.ResponseBook {
+	return entity.ResponseBook{
+		ID:       providerBook.ID,
+		Provider: providerBook.Provider,
+		Title:    providerBook.Title,
+		// 著者が複数いる場合はカンマで区切る
+		Author:        strings.Join(providerBook.Authors, ", "),
+		ThumbnailLink: providerBook.ThumbnailLink,
+		PublishedDate: providerBook.PublishedDate,
+		NumOfPages:    providerBook.NumOfPages,
+		ISBN10:        providerBook.ISBN10,
+		ISBN13:        providerBook.ISBN13,
+		Categories:    providerBook.Categories,
+		Description:   providerBook.Description,
+		Publisher:     providerBook.Publisher,
+		Language:      providerBook.Language,
+		IsForSale:     providerBook.IsForSale,
+		Price:         providerBook.Price,
+		BuyLink:       providerBook.BuyLink,
+	}
 }
 
 // 対象のユーザが対象の書籍に対してレビューを行っているか判定する
-func isReviewed(userID uint, bookTitle string, bookAuthor string) bool {
+func isReviewed(userID uint, identity bookIdentity) bool {
 	db := db.GetDB()
 
-	// タイトルと著者をキーに、書籍データを取得
-	var book Book
-	if err := db.Where("title = ? AND author = ?", bookTitle, bookAuthor).First(&book).Error; err != nil {
+	// ISBN等の識別子、またはタイトルと著者をキーに、書籍データを取得
+	book, err := findBook(db, identity)
+	if err != nil {
 		return false
 	}
 
-	// ユーザIDと書籍IDをキーに、レビューデータを取得
-	var review Review
-	if err := db.Where("user_id = ? AND book_id = ?", userID, book.ID).First(&review).Error; err != nil {
+	// ユーザIDと書籍をキーに、同じ作品(いずれかの版)に対するレビューデータを取得
+	if _, err := findWorkReview(db, userID, book); err != nil {
 		return false
 	}
 	return true
 }
+
+// 書籍の同一性を判定するための情報
+type bookIdentity struct {
+	Title    string
+	Author   string
+	ISBN10   string
+	ISBN13   string
+	Provider string
+	VolumeID string
+}
+
+// 書籍検索レスポンス用の書籍構造体から、書籍の同一性を判定するための情報を生成する
+func bookIdentityFromResponseBook(book entity.ResponseBook) bookIdentity {
+	isbn10, isbn13 := normalizeISBNPair(book.ISBN10, book.ISBN13)
+	return bookIdentity{
+		Title:    book.Title,
+		Author:   book.Author,
+		ISBN10:   isbn10,
+		ISBN13:   isbn13,
+		Provider: book.Provider,
+		VolumeID: book.ID,
+	}
+}
+
+// 書籍データを取得する
+// ISBN、書籍メタデータプロバイダのID、タイトルと著者の順に照合する
+func findBook(tx *gorm.DB, identity bookIdentity) (Book, error) {
+	var book Book
+
+	// ISBNで照合
+	if identity.ISBN13 != "" {
+		query := tx.Where("isbn13 = ?", identity.ISBN13)
+		if identity.ISBN10 != "" {
+			query = query.Or("isbn10 = ?", identity.ISBN10)
+		}
+		err := query.First(&book).Error
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return book, err
+		}
+	}
+
+	// 書籍メタデータプロバイダのIDで照合
+	if identity.Provider != "" && identity.VolumeID != "" {
+		err := tx.Where("provider = ? AND volume_id = ?", identity.Provider, identity.VolumeID).First(&book).Error
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return book, err
+		}
+	}
+
+	// タイトルと著者で照合
+	// ISBNが指定されている場合、異なるISBNを持つ書籍は別の版とみなすため照合しない
+	query := tx
+	if identity.ISBN13 != "" {
+		query = query.Where("coalesce(isbn13, '') = ''")
+	}
+	err := query.Session(&gorm.Session{}).Where("title = ? AND author = ?", identity.Title, identity.Author).First(&book).Error
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return book, err
+	}
+	// 完全一致しない場合は、大文字小文字と空白の違いを無視して照合
+	/*
+		SELECT * FROM books
+		WHERE lower(regexp_replace(title, '\s+', '', 'g')) = [タイトル]
+		AND lower(regexp_replace(author, '\s+', '', 'g')) = [著者]
+		ORDER BY id LIMIT 1;
+	*/
+	err = query.Where("lower(regexp_replace(title, '\\s+', '', 'g')) = ? AND lower(regexp_replace(author, '\\s+', '', 'g')) = ?",
+		compactLower(identity.Title), compactLower(identity.Author)).First(&book).Error
+	if err != nil {
+		return Book{}, err
+	}
+	return book, nil
+}
+
+// 書籍を新規登録し、著者、ジャンル、作品を関連付ける(登録したユーザを変更履歴に記録する)
+// ジャンルは書籍の分類から判定し、著者の指定が無い場合は著者文字列から登録し、作品IDの指定が無い場合はタイトルと著者が一致する作品の版とする
+func createBook(tx *gorm.DB, book *Book, contributors []entity.ContributorRequest, workID, userID uint) error {
+	if err := tx.Create(book).Error; err != nil {
+		return err
+	}
+	if err := recordBookCreation(tx, *book, userID); err != nil {
+		return err
+	}
+	if len(contributors) == 0 {
+		contributors = parseContributors(book.Author)
+	}
+	if err := linkBookAuthors(tx, book.ID, contributors); err != nil {
+		return err
+	}
+	if err := assignGenres(tx, *book); err != nil {
+		return err
+	}
+	return assignWork(tx, book, workID)
+}
+
+// 書籍を新規作成する
+// 同じISBN13、書籍メタデータプロバイダのIDの書籍が同時に登録され一意制約に違反した場合は、登録済みの書籍を読み直す
+// (登録済みの書籍を読み直した場合はtrueを返す)
+func createOrFindBook(tx *gorm.DB, book *Book, contributors []entity.ContributorRequest, workID, userID uint) (bool, error) {
+	// 一意制約に違反してもトランザクションを続けられるよう、セーブポイントを設定する
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		return createBook(tx, book, contributors, workID, userID)
+	})
+	if !isUniqueViolation(err) {
+		return false, err
+	}
+
+	existing, err := findBook(tx, bookIdentity{
+		Title:    book.Title,
+		Author:   book.Author,
+		ISBN10:   book.ISBN10,
+		ISBN13:   book.ISBN13,
+		Provider: book.Provider,
+		VolumeID: book.VolumeID,
+	})
+	if err != nil {
+		return false, err
+	}
+	*book = existing
+	return true, nil
+}
+
+// 一意制約違反のエラーか判定する
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" // unique_violation
+}
+
+// 取得済みの書籍データに無い識別子を、照合に用いた情報で補完する
+// 他の書籍が同じ識別子を持つ場合(一意制約違反)は補完しない
+func fillBookIdentity(tx *gorm.DB, book *Book, identity bookIdentity) error {
+	updates := map[string]interface{}{}
+	if book.ISBN13 == "" && identity.ISBN13 != "" {
+		updates["isbn13"] = identity.ISBN13
+	}
+	if book.ISBN10 == "" && identity.ISBN10 != "" {
+		updates["isbn10"] = identity.ISBN10
+	}
+	if book.VolumeID == "" && identity.Provider != "" && identity.VolumeID != "" {
+		updates["provider"] = identity.Provider
+		updates["volume_id"] = identity.VolumeID
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&Book{}).Where("id = ?", book.ID).Updates(updates).Error
+	})
+	if isUniqueViolation(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 保存できた場合のみ、取得済みの書籍データに反映する
+	if _, ok := updates["isbn13"]; ok {
+		book.ISBN13 = identity.ISBN13
+	}
+	if _, ok := updates["isbn10"]; ok {
+		book.ISBN10 = identity.ISBN10
+	}
+	if _, ok := updates["volume_id"]; ok {
+		book.Provider = identity.Provider
+		book.VolumeID = identity.VolumeID
+	}
+	return nil
+}
+
+// 空白を取り除き、小文字にする
+func compactLower(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"一意制約違反", &pgconn.PgError{Code: "23505"}, true},
+		{"ラップされた一意制約違反", fmt.Errorf("create book: %w", &pgconn.PgError{Code: "23505"}), true},
+		{"外部キー制約違反", &pgconn.PgError{Code: "23503"}, false},
+		{"その他のエラー", errors.New("connection refused"), false},
+		{"エラー無し", nil, false},
+	}
+	for _, tt := range tests {
+		if got := isUniqueViolation(tt.err); got != tt.want {
+			t.Errorf("%s: isUniqueViolation() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type BulkReviewRequest entity.BulkReviewRequest
+type BulkReviewResponse entity.BulkReviewResponse
+
+// 一括操作のレビューごとの処理結果
+const (
+	bulkResultUpdated = "updated"
+	bulkResultDeleted = "deleted"
+	bulkResultFailed  = "failed"
+)
+
+// レビュー一括操作サービス
+// 全てのレビューに対する操作を1つのトランザクションで行い、1件でも失敗した場合は全ての変更を取り消す
+func (s Service) BulkUpdateReviews(c *gin.Context) (BulkReviewResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request BulkReviewRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return BulkReviewResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return BulkReviewResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return BulkReviewResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return BulkReviewResponse{}, http.StatusBadRequest, err
+	}
+	for _, operation := range request.Operations {
+		if operation.Type == entity.BulkOperationSetVisibility && operation.Visibility != entity.ReviewVisibilityPublic && operation.Visibility != entity.ReviewVisibilityPrivate {
+			return BulkReviewResponse{}, http.StatusBadRequest, fmt.Errorf("visibility must be public or private")
+		}
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return BulkReviewResponse{}, http.StatusNotFound, err
+	}
+
+	// 削除操作を含む場合は、削除として権限を検証する
+	operationName := "update"
+	for _, operation := range request.Operations {
+		if operation.Type == entity.BulkOperationDelete {
+			operationName = "delete"
+		}
+	}
+
+	response := BulkReviewResponse{Results: []entity.BulkReviewResult{}}
+	var failedStatusCode StatusCode
+	today := userToday(user)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, reviewID := range request.ReviewIDs {
+			result := entity.BulkReviewResult{ReviewID: reviewID}
+
+			status, statusCode, err := applyBulkOperations(tx, reviewID, user.ID, operationName, request.Operations, today)
+			if err != nil {
+				result.Status = bulkResultFailed
+				result.Error = err.Error()
+				if failedStatusCode == 0 {
+					failedStatusCode = statusCode
+				}
+			} else {
+				result.Status = status
+			}
+
+			response.Results = append(response.Results, result)
+		}
+
+		if failedStatusCode != 0 {
+			return fmt.Errorf("some reviews couldn't be processed, no changes were applied")
+		}
+		return nil
+	})
+	if err != nil {
+		if failedStatusCode == 0 {
+			failedStatusCode = http.StatusInternalServerError
+		}
+		return response, failedStatusCode, err
+	}
+
+	response.Applied = true
+	return response, http.StatusOK, nil
+}
+
+// レビュー1件に対して、一括操作を順に適用する(todayは読了日を補完する場合の、ユーザのタイムゾーンでの今日の日付)
+func applyBulkOperations(tx *gorm.DB, reviewID, userID uint, operationName string, operations []entity.BulkReviewOperation, today time.Time) (string, StatusCode, error) {
+	// レビュー更新、削除と同じく、ログインユーザのレビューであるか検証する
+	review, statusCode, err := findOwnReview(tx, reviewID, userID, operationName)
+	if err != nil {
+		return "", statusCode, err
+	}
+
+	before := review
+	for _, operation := range operations {
+		switch operation.Type {
+		case entity.BulkOperationSetStatus:
+			review.ReadingStatus = operation.ReadingStatus
+			review.FinishReadAt = defaultFinishReadAt(review.ReadingStatus, review.FinishReadAt, today)
+		case entity.BulkOperationAddTags:
+			review.Tags = addTags(review.Tags, operation.Tags)
+		case entity.BulkOperationRemoveTags:
+			review.Tags = removeTags(review.Tags, operation.Tags)
+		case entity.BulkOperationSetVisibility:
+			review.Visibility = operation.Visibility
+		case entity.BulkOperationDelete:
+			if err := tx.Delete(&review).Error; err != nil {
+				return "", http.StatusInternalServerError, err
+			}
+			if err := refreshMonthlyReviewStats(tx, userID, review.FinishReadAt); err != nil {
+				return "", http.StatusInternalServerError, err
+			}
+			return bulkResultDeleted, http.StatusOK, nil
+		}
+	}
+
+	if err := tx.Save(&review).Error; err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if err := recordReadingActivities(tx, before, review); err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if err := refreshMonthlyReviewStats(tx, userID, before.FinishReadAt, review.FinishReadAt); err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	return bulkResultUpdated, http.StatusOK, nil
+}
+
+// カンマ区切りのタグ文字列に、タグを追加する(登録済みのタグは追加しない)
+func addTags(tags string, newTags []string) string {
+	result := splitTags(tags)
+	for _, tag := range newTags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !containsString(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, ",")
+}
+
+// カンマ区切りのタグ文字列から、タグを削除する
+func removeTags(tags string, targetTags []string) string {
+	var result []string
+	for _, tag := range splitTags(tags) {
+		if !containsString(targetTags, tag) {
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, ",")
+}
+
+// カンマ区切りのタグ文字列を、タグのスライスに変換する
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/cover"
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// 表紙画像のキャッシュの有効期間
+const (
+	coverCacheControl       = "public, max-age=604800" // 取得した表紙画像(7日間)
+	placeholderCacheControl = "public, max-age=3600"   // プレースホルダ(表紙画像が登録された場合に反映されるよう、1時間)
+)
+
+// 書籍の表紙画像取得サービス
+// 書籍メタデータプロバイダの表紙画像を取得、リサイズしてキャッシュし、無い場合はプレースホルダ画像を返す
+func (s Service) GetBookCover(c *gin.Context) (entity.CoverImage, StatusCode, error) {
+	db := db.GetDB()
+	var book Book
+
+	// サイズの検証
+	size := c.DefaultQuery("size", cover.DefaultSize)
+	width, ok := cover.Sizes[size]
+	if !ok {
+		return entity.CoverImage{}, http.StatusBadRequest, fmt.Errorf("size must be one of small, medium, large")
+	}
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return entity.CoverImage{}, http.StatusNotFound, err
+	}
+
+	image := cover.Image{}
+	cacheControl := coverCacheControl
+	if book.ThumbnailLink != "" {
+		var err error
+		image, err = cover.Default().Get(c.Request.Context(), book.ThumbnailLink, width)
+		if err != nil {
+			// 取得できない場合はプレースホルダを返す
+			log.Printf("failed to get cover of book %d: %v", book.ID, err)
+		}
+	}
+	if len(image.Data) == 0 {
+		image = cover.Placeholder(book.Title, book.Author, width)
+		cacheControl = placeholderCacheControl
+	}
+
+	response := entity.CoverImage{
+		Data:         image.Data,
+		ContentType:  image.ContentType,
+		ETag:         `"` + strings.ReplaceAll(image.ETag, "/", "-") + `"`,
+		CacheControl: cacheControl,
+	}
+	if c.GetHeader("If-None-Match") == response.ETag {
+		response.NotModified = true
+		response.Data = nil
+	}
+	return response, http.StatusOK, nil
+}
+
+// 書籍の表紙画像のURLを返す
+func bookCoverLink(bookID uint) string {
+	return fmt.Sprintf("/book/%d/cover", bookID)
+}
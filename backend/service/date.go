@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// ユーザのタイムゾーンが未設定の場合に、日付の集計に用いるタイムゾーン
+const defaultTimeZone = "Asia/Tokyo"
+
+// 日付の形式
+const dateLayout = "2006-01-02"
+
+// タイムゾーン名(例: "Asia/Tokyo")からタイムゾーンを読み込む
+// サーバのタイムゾーンに依存しないよう、"Local"は指定できないものとする
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// ユーザのタイムゾーンを返す(未設定、または不正な場合は既定のタイムゾーン)
+func userLocation(user User) *time.Location {
+	for _, name := range []string{user.TimeZone, defaultTimeZone} {
+		if name == "" {
+			continue
+		}
+		if location, err := loadTimeZone(name); err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+// ユーザのタイムゾーンでの今日の日付を返す
+func userToday(user User) time.Time {
+	return userDate(user, time.Now())
+}
+
+// 日時の、ユーザのタイムゾーンでの日付を返す
+func userDate(user User, t time.Time) time.Time {
+	return dateOf(t.In(userLocation(user)))
+}
+
+// 日時の、そのタイムゾーンでの日付を返す
+// 読了日等の日付のカラムと比較するため、UTCの0時とする(日付のカラムはタイムゾーンを持たない暦日として扱う)
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// 年の期間を、開始日と翌年の開始日の半開区間[start, end)で返す
+func yearRange(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// 月の期間を、開始日と翌月の開始日の半開区間[start, end)で返す
+// (月末の日付を求めずに済み、月末の日の0時以降の日時も含まれる)
+func monthRange(year int, month time.Month) (time.Time, time.Time) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// 日付を"2006-01-02"形式の文字列にする(未設定の場合は空文字)
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// "2006-01-02"形式の文字列を日付にする(空文字の場合は未設定とする)
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(dateLayout, s)
+}
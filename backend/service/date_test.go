@@ -0,0 +1,239 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+)
+
+// 日付のテスト用に、"2006-01-02"形式の文字列を日付にする
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	date, err := time.Parse(dateLayout, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return date
+}
+
+// 半開区間[start, end)に含まれるか
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
+
+func TestMonthRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		year      int
+		month     time.Month
+		wantStart string
+		wantEnd   string
+	}{
+		{"1月", 2023, time.January, "2023-01-01", "2023-02-01"},
+		{"閏年の2月", 2024, time.February, "2024-02-01", "2024-03-01"},
+		{"平年の2月", 2023, time.February, "2023-02-01", "2023-03-01"},
+		{"12月(年を跨ぐ)", 2023, time.December, "2023-12-01", "2024-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := monthRange(tt.year, tt.month)
+			if formatDate(start) != tt.wantStart || formatDate(end) != tt.wantEnd {
+				t.Errorf("monthRange(%d, %d) = [%s, %s), want [%s, %s)", tt.year, tt.month, formatDate(start), formatDate(end), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+// 月末の日の0時以降の日時が、その月に含まれ、翌月に含まれないこと
+func TestMonthRangeIncludesEndOfMonth(t *testing.T) {
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		last  string
+	}{
+		{"閏年の2月29日", 2024, time.February, "2024-02-29"},
+		{"平年の2月28日", 2023, time.February, "2023-02-28"},
+		{"4月30日", 2023, time.April, "2023-04-30"},
+		{"12月31日", 2023, time.December, "2023-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := monthRange(tt.year, tt.month)
+			last := mustDate(t, tt.last)
+			for _, finishReadAt := range []time.Time{last, last.Add(23*time.Hour + 59*time.Minute)} {
+				if !inRange(finishReadAt, start, end) {
+					t.Errorf("%s is not in [%s, %s)", finishReadAt, start, end)
+				}
+			}
+			if next := last.AddDate(0, 0, 1); inRange(next, start, end) {
+				t.Errorf("%s is in [%s, %s)", next, start, end)
+			}
+		})
+	}
+}
+
+func TestYearRange(t *testing.T) {
+	tests := []struct {
+		year      int
+		wantStart string
+		wantEnd   string
+	}{
+		{2023, "2023-01-01", "2024-01-01"},
+		{2024, "2024-01-01", "2025-01-01"},
+	}
+	for _, tt := range tests {
+		start, end := yearRange(tt.year)
+		if formatDate(start) != tt.wantStart || formatDate(end) != tt.wantEnd {
+			t.Errorf("yearRange(%d) = [%s, %s), want [%s, %s)", tt.year, formatDate(start), formatDate(end), tt.wantStart, tt.wantEnd)
+		}
+		if last := time.Date(tt.year, time.December, 31, 23, 59, 59, 0, time.UTC); !inRange(last, start, end) {
+			t.Errorf("%s is not in [%s, %s)", last, start, end)
+		}
+	}
+}
+
+func TestUserDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeZone string
+		utc      string // UTCでの日時(RFC3339形式)
+		want     string
+	}{
+		// America/New_Yorkは2024-03-10 2:00(EST)に夏時間が始まり、2024-11-03 2:00(EDT)に終わる
+		{"夏時間の開始前", "America/New_York", "2024-03-10T06:59:00Z", "2024-03-10"},
+		{"夏時間の開始後", "America/New_York", "2024-03-10T07:00:00Z", "2024-03-10"},
+		{"夏時間中の深夜", "America/New_York", "2024-03-11T03:59:00Z", "2024-03-10"},
+		{"夏時間中の日付の変わり目", "America/New_York", "2024-03-11T04:00:00Z", "2024-03-11"},
+		{"夏時間の終了前", "America/New_York", "2024-11-03T05:59:00Z", "2024-11-03"},
+		{"夏時間の終了後の深夜", "America/New_York", "2024-11-04T04:59:00Z", "2024-11-03"},
+		{"夏時間の終了後の日付の変わり目", "America/New_York", "2024-11-04T05:00:00Z", "2024-11-04"},
+		{"UTCの深夜0時前の東京", "Asia/Tokyo", "2024-02-29T14:59:00Z", "2024-02-29"},
+		{"UTCの深夜0時前の東京(日付の変わり目)", "Asia/Tokyo", "2024-02-29T15:00:00Z", "2024-03-01"},
+		{"UTCの月末の東京", "Asia/Tokyo", "2023-12-31T23:30:00Z", "2024-01-01"},
+		{"未設定の場合は既定のタイムゾーン", "", "2023-12-31T15:00:00Z", "2024-01-01"},
+		{"不正な場合は既定のタイムゾーン", "Invalid/Zone", "2023-12-31T15:00:00Z", "2024-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utc, err := time.Parse(time.RFC3339, tt.utc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := userDate(User{TimeZone: tt.timeZone}, utc)
+			if formatDate(got) != tt.want {
+				t.Errorf("userDate(%q, %s) = %s, want %s", tt.timeZone, tt.utc, formatDate(got), tt.want)
+			}
+			if got.Location() != time.UTC || got.Hour() != 0 {
+				t.Errorf("userDate(%q, %s) = %s, want UTC midnight", tt.timeZone, tt.utc, got)
+			}
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Asia/Tokyo", false},
+		{"America/New_York", false},
+		{"UTC", false},
+		{"Local", true},
+		{"Invalid/Zone", true},
+	}
+	for _, tt := range tests {
+		if _, err := loadTimeZone(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("loadTimeZone(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if got := userLocation(User{TimeZone: "Local"}).String(); got != defaultTimeZone {
+		t.Errorf("userLocation(Local) = %s, want %s", got, defaultTimeZone)
+	}
+}
+
+func TestParseAndFormatDate(t *testing.T) {
+	if date, err := parseDate(""); err != nil || !date.IsZero() || formatDate(date) != "" {
+		t.Errorf(`parseDate("") = %s, %v, want zero time`, date, err)
+	}
+	if _, err := parseDate("2023-02-29"); err == nil {
+		t.Error(`parseDate("2023-02-29") want error`)
+	}
+	if date, err := parseDate("2024-02-29"); err != nil || formatDate(date) != "2024-02-29" {
+		t.Errorf(`parseDate("2024-02-29") = %s, %v`, date, err)
+	}
+}
+
+// 詳細な読書統計情報の期間の最終月の、月末の読了日が集計の期間に含まれること
+func TestStatsMonthRangeIncludesEndOfMonth(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		today    string
+		last     string // 期間の最終日
+		wantFrom string
+	}{
+		{"閏年の2月まで", "2023-03", "2024-02", "2024-06-15", "2024-02-29", "2023-03-01"},
+		{"平年の2月まで", "2022-03", "2023-02", "2024-06-15", "2023-02-28", "2022-03-01"},
+		{"12月まで", "2023-01", "2023-12", "2024-06-15", "2023-12-31", "2023-01-01"},
+		{"未指定の場合は今月まで", "", "", "2024-02-10", "2024-02-29", "2023-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := statsMonthRange(tt.from, tt.to, mustDate(t, tt.today))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatDate(from) != tt.wantFrom {
+				t.Errorf("from = %s, want %s", formatDate(from), tt.wantFrom)
+			}
+			// GetDetailedReviewStatsと同じく、最終月の翌月の1日までの半開区間とする
+			end := to.AddDate(0, 1, 0)
+			last := mustDate(t, tt.last).Add(23 * time.Hour)
+			if !inRange(last, from, end) {
+				t.Errorf("%s is not in [%s, %s)", last, from, end)
+			}
+			if next := mustDate(t, tt.last).AddDate(0, 0, 1); inRange(next, from, end) {
+				t.Errorf("%s is in [%s, %s)", next, from, end)
+			}
+		})
+	}
+}
+
+// 読書目標の期間の最終日の読了日が、進捗の集計の期間に含まれること
+func TestGoalDateRangeIncludesEndOfPeriod(t *testing.T) {
+	today := mustDate(t, "2024-02-10")
+	tests := []struct {
+		name      string
+		request   CreateGoalRequest
+		wantStart string
+		wantEnd   string
+	}{
+		{"閏年の2月", CreateGoalRequest{Period: entity.GoalPeriodMonth, Year: 2024, Month: 2}, "2024-02-01", "2024-02-29"},
+		{"平年の2月", CreateGoalRequest{Period: entity.GoalPeriodMonth, Year: 2023, Month: 2}, "2023-02-01", "2023-02-28"},
+		{"12月", CreateGoalRequest{Period: entity.GoalPeriodMonth, Year: 2023, Month: 12}, "2023-12-01", "2023-12-31"},
+		{"未指定の場合は今月", CreateGoalRequest{Period: entity.GoalPeriodMonth}, "2024-02-01", "2024-02-29"},
+		{"年", CreateGoalRequest{Period: entity.GoalPeriodYear, Year: 2024}, "2024-01-01", "2024-12-31"},
+		{"期間指定", CreateGoalRequest{Period: entity.GoalPeriodCustom, StartDate: "2024-02-15", EndDate: "2024-03-31"}, "2024-02-15", "2024-03-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startDate, endDate, err := goalDateRange(tt.request, today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatDate(startDate) != tt.wantStart || formatDate(endDate) != tt.wantEnd {
+				t.Fatalf("goalDateRange() = %s, %s, want %s, %s", formatDate(startDate), formatDate(endDate), tt.wantStart, tt.wantEnd)
+			}
+			_, end := goalPeriodRange(startDate, endDate)
+			if last := endDate.Add(23 * time.Hour); !inRange(last, startDate, end) {
+				t.Errorf("%s is not in [%s, %s)", last, startDate, end)
+			}
+			if next := endDate.AddDate(0, 0, 1); inRange(next, startDate, end) {
+				t.Errorf("%s is in [%s, %s)", next, startDate, end)
+			}
+		})
+	}
+}
@@ -0,0 +1,703 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DuplicateCandidate entity.DuplicateCandidate
+type MergeBooksRequest entity.MergeBooksRequest
+
+// 重複候補とする類似度の下限
+const duplicateScoreThreshold = 0.75
+
+// 重複検出で比較する書籍のグループの上限(著者の書籍が非常に多い場合に、比較回数が増えすぎないようにする)
+const maxDuplicateBlockSize = 200
+
+// 重複検出の実行間隔の既定値
+const defaultDuplicateScanInterval = 24 * time.Hour
+
+// 重複検出を同時に実行しないためのロック
+var duplicateScanMutex sync.Mutex
+
+// 重複検出に用いる書籍の情報
+type duplicateBookKeys struct {
+	Book         Book
+	TitleKey     string          // 正規化したタイトル
+	MainTitleKey string          // 副題を除いて正規化したタイトル
+	AuthorKey    string          // 正規化した著者文字列
+	AuthorNames  map[string]bool // 正規化した著者名
+	AuthorIDs    map[string]bool // 著者ID(別名の場合は正式な著者のID)
+}
+
+// 書籍の重複候補一覧取得サービス
+// 類似度の高い順に返す(ステータスが指定されない場合は未確認の候補のみ)
+func (s Service) GetDuplicateCandidates(c *gin.Context) ([]entity.ResponseDuplicateCandidate, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.ResponseDuplicateCandidate{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.ResponseDuplicateCandidate{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.ResponseDuplicateCandidate{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return []entity.ResponseDuplicateCandidate{}, http.StatusForbidden, errors.New("only moderators can manage duplicate books")
+	}
+
+	// 類似度の下限の指定を取得
+	minScore := 0.0
+	if c.Query("minScore") != "" {
+		if minScore, err = strconv.ParseFloat(c.Query("minScore"), 64); err != nil {
+			return []entity.ResponseDuplicateCandidate{}, http.StatusBadRequest, err
+		}
+	}
+
+	var rows []struct {
+		ID                    uint
+		Score                 float64
+		Reasons               string
+		Status                string
+		BookID                uint
+		BookTitle             string
+		BookAuthor            string
+		BookThumbnailLink     string
+		BookPublishedDate     string
+		BookISBN13            string `gorm:"column:book_isbn13"`
+		BookNumOfReviews      int64
+		OtherBookID           uint
+		OtherBookTitle        string
+		OtherBookAuthor       string
+		OtherBookThumbnail    string
+		OtherBookPublished    string
+		OtherBookISBN13       string `gorm:"column:other_book_isbn13"`
+		OtherBookNumOfReviews int64
+	}
+	err = db.Model(&DuplicateCandidate{}).
+		Select(`duplicate_candidates.id, duplicate_candidates.score, duplicate_candidates.reasons, duplicate_candidates.status,
+			books.id as book_id, books.title as book_title, books.author as book_author, books.thumbnail_link as book_thumbnail_link,
+			books.published_date as book_published_date, books.isbn13 as book_isbn13,
+			(SELECT count(*) FROM reviews WHERE reviews.book_id = books.id) as book_num_of_reviews,
+			other_books.id as other_book_id, other_books.title as other_book_title, other_books.author as other_book_author,
+			other_books.thumbnail_link as other_book_thumbnail, other_books.published_date as other_book_published,
+			other_books.isbn13 as other_book_isbn13,
+			(SELECT count(*) FROM reviews WHERE reviews.book_id = other_books.id) as other_book_num_of_reviews`).
+		Joins("join books on books.id = duplicate_candidates.book_id").
+		Joins("join books other_books on other_books.id = duplicate_candidates.other_book_id").
+		Where("duplicate_candidates.status = ? AND duplicate_candidates.score >= ?", c.DefaultQuery("status", entity.DuplicateStatusPending), minScore).
+		Order("duplicate_candidates.score desc, duplicate_candidates.id").
+		Scan(&rows).Error
+	if err != nil {
+		// SELECT duplicate_candidates.*, [書籍の項目], [書籍のレビュー件数], [もう一方の書籍の項目], [もう一方の書籍のレビュー件数]
+		// FROM duplicate_candidates
+		// join books on books.id = duplicate_candidates.book_id
+		// join books other_books on other_books.id = duplicate_candidates.other_book_id
+		// WHERE duplicate_candidates.status = [ステータス] AND duplicate_candidates.score >= [類似度の下限]
+		// ORDER BY duplicate_candidates.score desc, duplicate_candidates.id
+		return []entity.ResponseDuplicateCandidate{}, http.StatusInternalServerError, err
+	}
+
+	candidates := []entity.ResponseDuplicateCandidate{}
+	for _, row := range rows {
+		candidates = append(candidates, entity.ResponseDuplicateCandidate{
+			ID:      row.ID,
+			Score:   row.Score,
+			Reasons: splitTags(row.Reasons),
+			Status:  row.Status,
+			Book: entity.DuplicateBook{
+				ID:            row.BookID,
+				Title:         row.BookTitle,
+				Author:        row.BookAuthor,
+				ThumbnailLink: row.BookThumbnailLink,
+				PublishedDate: row.BookPublishedDate,
+				ISBN13:        row.BookISBN13,
+				NumOfReviews:  row.BookNumOfReviews,
+			},
+			OtherBook: entity.DuplicateBook{
+				ID:            row.OtherBookID,
+				Title:         row.OtherBookTitle,
+				Author:        row.OtherBookAuthor,
+				ThumbnailLink: row.OtherBookThumbnail,
+				PublishedDate: row.OtherBookPublished,
+				ISBN13:        row.OtherBookISBN13,
+				NumOfReviews:  row.OtherBookNumOfReviews,
+			},
+		})
+	}
+	return candidates, http.StatusOK, nil
+}
+
+// 書籍の重複検出サービス
+// 重複検出をバックグラウンドで開始する(検出結果は重複候補一覧で確認する)
+func (s Service) ScanDuplicateBooks(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return http.StatusForbidden, errors.New("only moderators can manage duplicate books")
+	}
+
+	if !duplicateScanMutex.TryLock() {
+		return http.StatusConflict, errors.New("duplicate scan is already running")
+	}
+	go func() {
+		defer duplicateScanMutex.Unlock()
+		if err := scanDuplicateBooks(); err != nil {
+			log.Printf("failed to scan duplicate books: %v", err)
+		}
+	}()
+	return http.StatusAccepted, nil
+}
+
+// 書籍の重複候補の却下サービス
+// 重複ではないと判断した候補は、以降の重複検出でも候補としない
+func (s Service) DismissDuplicateCandidate(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var candidate DuplicateCandidate
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return http.StatusForbidden, errors.New("only moderators can manage duplicate books")
+	}
+
+	// IDをキーに、重複候補を取得
+	if err := db.Where("id = ?", c.Param("candidateId")).First(&candidate).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+	if err := db.Model(&candidate).Update("status", entity.DuplicateStatusDismissed).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// 書籍の統合サービス
+// 重複した書籍のレビュー等を統合先の書籍に付け替え、重複した書籍を削除する
+func (s Service) MergeBooks(c *gin.Context) (entity.MergeBooksResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request MergeBooksRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.MergeBooksResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.MergeBooksResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.MergeBooksResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.MergeBooksResponse{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.MergeBooksResponse{}, http.StatusNotFound, err
+	}
+	if !user.IsModerator {
+		return entity.MergeBooksResponse{}, http.StatusForbidden, errors.New("only moderators can manage duplicate books")
+	}
+
+	response := entity.MergeBooksResponse{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var survivor, duplicate Book
+
+		// 統合中に更新されないよう、書籍をロックして取得
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", c.Param("id")).First(&survivor).Error; err != nil {
+			statusCode = http.StatusNotFound
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", request.DuplicateID).First(&duplicate).Error; err != nil {
+			statusCode = http.StatusNotFound
+			return err
+		}
+		if survivor.ID == duplicate.ID {
+			statusCode = http.StatusBadRequest
+			return errors.New("cannot merge a book into itself")
+		}
+
+		// 両方の書籍をレビューしたユーザがいる場合は、1冊に2件のレビューとなるため統合しない
+		names, err := reviewersOfBothBooks(tx, survivor.ID, duplicate.ID)
+		if err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		if len(names) > 0 {
+			statusCode = http.StatusConflict
+			return fmt.Errorf("both books are reviewed by the same users: %s", strings.Join(names, ", "))
+		}
+
+		moved, err := mergeBooks(tx, survivor, duplicate, user.ID)
+		if err != nil {
+			statusCode = http.StatusInternalServerError
+			return err
+		}
+		response = entity.MergeBooksResponse{BookID: survivor.ID, MergedBookID: duplicate.ID, NumOfMovedReviews: moved}
+		return nil
+	})
+	if err != nil {
+		return entity.MergeBooksResponse{}, statusCode, err
+	}
+	return response, http.StatusOK, nil
+}
+
+// 重複検出を定期的に実行する
+// 実行間隔は環境変数DUPLICATE_SCAN_INTERVAL(例: "12h")で指定し、"0"の場合は実行しない
+func StartDuplicateDetection() {
+	interval := defaultDuplicateScanInterval
+	if value := os.Getenv("DUPLICATE_SCAN_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("invalid DUPLICATE_SCAN_INTERVAL %q: %v", value, err)
+		} else {
+			interval = parsed
+		}
+	}
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			if duplicateScanMutex.TryLock() {
+				if err := scanDuplicateBooks(); err != nil {
+					log.Printf("failed to scan duplicate books: %v", err)
+				}
+				duplicateScanMutex.Unlock()
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// 全書籍を比較して重複候補を登録する
+// 今回の検出で候補とならなかった未確認の候補は削除し、却下済みの候補はそのまま残す
+func scanDuplicateBooks() error {
+	db := db.GetDB()
+	scannedAt := time.Now().Unix()
+
+	books, err := loadDuplicateBookKeys(db)
+	if err != nil {
+		return err
+	}
+
+	// 比較する書籍をグループに分ける(同じISBN、プロバイダのID、副題を除いたタイトル、著者を持つ書籍を比較する)
+	blocks := map[string][]int{}
+	for i, book := range books {
+		keys := []string{"title:" + book.MainTitleKey}
+		if book.Book.ISBN13 != "" {
+			keys = append(keys, "isbn13:"+book.Book.ISBN13)
+		}
+		if book.Book.ISBN10 != "" {
+			keys = append(keys, "isbn10:"+book.Book.ISBN10)
+		}
+		if book.Book.VolumeID != "" {
+			keys = append(keys, "volume:"+book.Book.Provider+":"+book.Book.VolumeID)
+		}
+		for name := range book.AuthorNames {
+			keys = append(keys, "author:"+name)
+		}
+		for id := range book.AuthorIDs {
+			keys = append(keys, "authorId:"+id)
+		}
+		for _, key := range keys {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	compared := map[[2]int]bool{}
+	found := 0
+	for _, block := range blocks {
+		if len(block) < 2 || len(block) > maxDuplicateBlockSize {
+			continue
+		}
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				pair := [2]int{block[x], block[y]}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				score, reasons := duplicateScore(books[pair[0]], books[pair[1]])
+				if score < duplicateScoreThreshold {
+					continue
+				}
+				bookID, otherBookID := books[pair[0]].Book.ID, books[pair[1]].Book.ID
+				if bookID > otherBookID {
+					bookID, otherBookID = otherBookID, bookID
+				}
+				candidate := DuplicateCandidate{
+					Score:       score,
+					Reasons:     strings.Join(reasons, ","),
+					Status:      entity.DuplicateStatusPending,
+					ScannedAt:   scannedAt,
+					BookID:      bookID,
+					OtherBookID: otherBookID,
+				}
+				err := db.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "book_id"}, {Name: "other_book_id"}},
+					DoUpdates: clause.AssignmentColumns([]string{"score", "reasons", "scanned_at", "updated_at"}),
+				}).Create(&candidate).Error
+				if err != nil {
+					return err
+				}
+				found++
+			}
+		}
+	}
+
+	if err := db.Where("status = ? AND scanned_at < ?", entity.DuplicateStatusPending, scannedAt).Delete(&DuplicateCandidate{}).Error; err != nil {
+		return err
+	}
+	log.Printf("scanned %d books and found %d duplicate candidates", len(books), found)
+	return nil
+}
+
+// 全書籍について、重複検出に用いる情報を取得する
+func loadDuplicateBookKeys(db *gorm.DB) ([]duplicateBookKeys, error) {
+	var books []Book
+	if err := db.Select("id, title, author, isbn10, isbn13, provider, volume_id").Order("id").Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	// 書籍の著者(役割が著者のもの)を取得
+	var bookAuthors []struct {
+		BookID      uint
+		AuthorID    uint
+		CanonicalID uint
+		Name        string
+	}
+	err := db.Model(&entity.BookAuthor{}).Select("book_authors.book_id, book_authors.author_id, authors.canonical_id, authors.name").
+		Joins("join authors on authors.id = book_authors.author_id").
+		Where("book_authors.role = ?", entity.AuthorRoleAuthor).
+		Scan(&bookAuthors).Error
+	if err != nil {
+		// SELECT book_authors.book_id, book_authors.author_id, authors.canonical_id, authors.name
+		// FROM book_authors join authors on authors.id = book_authors.author_id
+		// WHERE book_authors.role = 'author'
+		return nil, err
+	}
+	authorsByBook := map[uint][]int{}
+	for i, bookAuthor := range bookAuthors {
+		authorsByBook[bookAuthor.BookID] = append(authorsByBook[bookAuthor.BookID], i)
+	}
+
+	result := make([]duplicateBookKeys, 0, len(books))
+	for _, book := range books {
+		keys := duplicateBookKeys{
+			Book:         book,
+			TitleKey:     normalizeText(book.Title),
+			MainTitleKey: normalizeMainTitle(book.Title),
+			AuthorKey:    authorNameKey(book.Author),
+			AuthorNames:  map[string]bool{},
+			AuthorIDs:    map[string]bool{},
+		}
+		for _, i := range authorsByBook[book.ID] {
+			authorID := bookAuthors[i].AuthorID
+			if bookAuthors[i].CanonicalID != 0 {
+				authorID = bookAuthors[i].CanonicalID
+			}
+			keys.AuthorIDs[strconv.FormatUint(uint64(authorID), 10)] = true
+			keys.AuthorNames[authorNameKey(bookAuthors[i].Name)] = true
+		}
+		// 著者が登録されていない書籍は、著者文字列から判定する
+		if len(keys.AuthorNames) == 0 {
+			for _, contributor := range parseContributors(book.Author) {
+				if contributor.Role == entity.AuthorRoleAuthor {
+					keys.AuthorNames[authorNameKey(contributor.Name)] = true
+				}
+			}
+		}
+		delete(keys.AuthorNames, "")
+		result = append(result, keys)
+	}
+	return result, nil
+}
+
+// 2つの書籍の類似度と、重複と判定した理由を返す
+func duplicateScore(a, b duplicateBookKeys) (float64, []string) {
+	// ISBN、書籍メタデータプロバイダのIDが一致する場合は重複とする
+	var reasons []string
+	if (a.Book.ISBN13 != "" && a.Book.ISBN13 == b.Book.ISBN13) || (a.Book.ISBN10 != "" && a.Book.ISBN10 == b.Book.ISBN10) {
+		reasons = append(reasons, entity.DuplicateReasonISBN)
+	}
+	if a.Book.VolumeID != "" && a.Book.Provider == b.Book.Provider && a.Book.VolumeID == b.Book.VolumeID {
+		reasons = append(reasons, entity.DuplicateReasonVolumeID)
+	}
+	if len(reasons) > 0 {
+		return 1, reasons
+	}
+
+	// 異なるISBNを持つ書籍、巻数のみ異なる書籍は別の書籍とみなす
+	if a.Book.ISBN13 != "" && b.Book.ISBN13 != "" {
+		return 0, nil
+	}
+	if isDifferentVolume(a.TitleKey, b.TitleKey) {
+		return 0, nil
+	}
+
+	// タイトルの類似度
+	var titleScore float64
+	switch {
+	case a.TitleKey == b.TitleKey:
+		titleScore = 1
+		reasons = append(reasons, entity.DuplicateReasonTitle)
+	case a.MainTitleKey != "" && a.MainTitleKey == b.MainTitleKey:
+		titleScore = 0.9
+		reasons = append(reasons, entity.DuplicateReasonMainTitle)
+	default:
+		titleScore = textSimilarity(a.TitleKey, b.TitleKey)
+		reasons = append(reasons, entity.DuplicateReasonSimilar)
+	}
+
+	// 著者が一致する場合はタイトルの類似度とし、一致しない場合は著者の類似度を加味する
+	if a.AuthorKey == b.AuthorKey || sharesKey(a.AuthorNames, b.AuthorNames) {
+		return roundScore(titleScore), append(reasons, entity.DuplicateReasonAuthor)
+	}
+	if sharesKey(a.AuthorIDs, b.AuthorIDs) {
+		return roundScore(titleScore), append(reasons, entity.DuplicateReasonAuthorAlias)
+	}
+	return roundScore(0.8*titleScore + 0.2*textSimilarity(a.AuthorKey, b.AuthorKey)), reasons
+}
+
+// 重複した書籍を統合先の書籍に統合し、付け替えたレビューの件数を返す
+// 統合先の書籍に無い項目は重複した書籍の値で補い、変更履歴に記録する
+func mergeBooks(tx *gorm.DB, survivor, duplicate Book, moderatorID uint) (int64, error) {
+	// 統合先の書籍に無い項目を補う
+	merged := survivor
+	if merged.ISBN13 == "" && merged.ISBN10 == "" {
+		merged.ISBN10, merged.ISBN13 = duplicate.ISBN10, duplicate.ISBN13
+	}
+	if merged.ThumbnailLink == "" {
+		merged.ThumbnailLink = duplicate.ThumbnailLink
+	}
+	if merged.PublishedDate == "" {
+		merged.PublishedDate = duplicate.PublishedDate
+	}
+	if merged.NumOfPages == 0 {
+		merged.NumOfPages = duplicate.NumOfPages
+	}
+	if merged.Categories == "" {
+		merged.Categories = duplicate.Categories
+	}
+	if merged.Description == "" {
+		merged.Description = duplicate.Description
+	}
+	if merged.Publisher == "" {
+		merged.Publisher = duplicate.Publisher
+	}
+	if merged.Language == "" {
+		merged.Language = duplicate.Language
+	}
+	changes := diffBookFields(survivor, merged)
+	columns := []string{"updated_at"}
+	for _, change := range changes {
+		columns = append(columns, bookFieldByName(change.Field).Column)
+	}
+	if (merged.VolumeID == "" || merged.Provider == manualBookProvider) && duplicate.VolumeID != "" {
+		merged.Provider, merged.VolumeID = duplicate.Provider, duplicate.VolumeID
+		columns = append(columns, "provider", "volume_id")
+	}
+	// 識別子の一意制約に違反しないよう、重複した書籍の識別子を先に消す
+	if err := tx.Model(&Book{}).Where("id = ?", duplicate.ID).Updates(map[string]interface{}{"isbn13": "", "volume_id": ""}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&merged).Select(columns).Updates(&merged).Error; err != nil {
+		return 0, err
+	}
+
+	// レビューを付け替え
+	result := tx.Model(&Review{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	// 統合によりページ数が変わる場合があるため、統合先の書籍を読了したユーザの統計を更新
+	if err := refreshBookReviewStats(tx, survivor.ID); err != nil {
+		return 0, err
+	}
+
+	// 統合先の書籍に著者が無い場合は、重複した書籍の著者を付け替え
+	var numOfAuthors int64
+	if err := tx.Model(&entity.BookAuthor{}).Where("book_id = ?", survivor.ID).Count(&numOfAuthors).Error; err != nil {
+		return 0, err
+	}
+	if numOfAuthors == 0 {
+		if err := tx.Model(&entity.BookAuthor{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// ジャンルは両方の書籍のものとする
+	if err := tx.Exec("INSERT INTO book_genres (book_id, genre_id) SELECT ?, genre_id FROM book_genres WHERE book_id = ? ON CONFLICT DO NOTHING", survivor.ID, duplicate.ID).Error; err != nil {
+		return 0, err
+	}
+
+	// 修正提案と変更履歴を付け替え
+	if err := tx.Model(&BookEdit{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&BookChange{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID).Error; err != nil {
+		return 0, err
+	}
+
+	// 価格履歴と通知を付け替え、価格の監視は統合先を監視していないユーザの分のみ付け替え(それ以外は書籍とともに削除)
+	if err := tx.Model(&PriceHistory{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&Notification{}).Where("book_id = ?", duplicate.ID).Update("book_id", survivor.ID).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&PriceWatch{}).Where("book_id = ? AND user_id NOT IN (?)", duplicate.ID, tx.Model(&PriceWatch{}).Select("user_id").Where("book_id = ?", survivor.ID)).Update("book_id", survivor.ID).Error; err != nil {
+		return 0, err
+	}
+
+	// 重複した書籍が作品の唯一の版の場合は、シリーズの巻を統合先の作品に付け替えて作品を削除
+	if duplicate.WorkID != 0 && survivor.WorkID != 0 && duplicate.WorkID != survivor.WorkID {
+		var numOfEditions int64
+		if err := tx.Model(&Book{}).Where("work_id = ? AND id <> ?", duplicate.WorkID, duplicate.ID).Count(&numOfEditions).Error; err != nil {
+			return 0, err
+		}
+		if numOfEditions == 0 {
+			// 統合先の作品が既に登録されているシリーズでは、巻を削除
+			if err := tx.Where("work_id = ? AND series_id IN (?)", duplicate.WorkID, tx.Model(&SeriesEntry{}).Select("series_id").Where("work_id = ?", survivor.WorkID)).Delete(&SeriesEntry{}).Error; err != nil {
+				return 0, err
+			}
+			if err := tx.Model(&SeriesEntry{}).Where("work_id = ?", duplicate.WorkID).Update("work_id", survivor.WorkID).Error; err != nil {
+				return 0, err
+			}
+			if err := tx.Delete(&Work{}, duplicate.WorkID).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// 統合を変更履歴に記録
+	changes = append(changes, entity.ResponseBookField{Field: "mergedBookId", NewValue: strconv.FormatUint(uint64(duplicate.ID), 10)})
+	for _, change := range changes {
+		bookChange := BookChange{
+			Action:      entity.BookChangeActionMerge,
+			Field:       change.Field,
+			OldValue:    change.OldValue,
+			NewValue:    change.NewValue,
+			UserID:      moderatorID,
+			ModeratorID: moderatorID,
+			BookID:      survivor.ID,
+		}
+		if err := tx.Create(&bookChange).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// 重複した書籍を削除(重複候補、著者、ジャンルの関連も削除される)
+	if err := tx.Delete(&Book{}, duplicate.ID).Error; err != nil {
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
+
+// 両方の書籍をレビューしたユーザの名前を返す
+func reviewersOfBothBooks(tx *gorm.DB, bookID, otherBookID uint) ([]string, error) {
+	var names []string
+	err := tx.Model(&User{}).
+		Where("id IN (?)", tx.Model(&Review{}).Select("user_id").Where("book_id = ?", bookID)).
+		Where("id IN (?)", tx.Model(&Review{}).Select("user_id").Where("book_id = ?", otherBookID)).
+		Order("name").
+		Pluck("name", &names).Error
+	return names, err
+}
+
+// 2つの集合に共通の要素があるか判定する
+func sharesKey(a, b map[string]bool) bool {
+	for key := range a {
+		if b[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// 類似度を小数点以下3桁に丸める
+func roundScore(score float64) float64 {
+	return float64(int(score*1000+0.5)) / 1000
+}
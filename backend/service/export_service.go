@@ -0,0 +1,322 @@
+package service
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+// レビューのエクスポート形式
+const (
+	exportFormatCSV      = "csv"
+	exportFormatJSON     = "json"
+	exportFormatMarkdown = "md"
+)
+
+// 何件書き出すごとにクライアントへ送信するか
+const exportFlushInterval = 100
+
+// CSV形式のエクスポートのヘッダ
+var exportCSVHeader = []string{"id", "bookTitle", "bookAuthor", "bookPublishedDate", "bookNumOfPages", "readingStatus", "rating", "readPages", "startReadAt", "finishReadAt", "tags", "comment"}
+
+// エクスポート用レビュー構造体
+type exportReview struct {
+	entity.ResponseReview
+	Year int
+}
+
+// レビューのエクスポート結果
+type ReviewExport struct {
+	ContentType string
+	FileName    string
+	format      string
+	db          *gorm.DB
+	rows        *sql.Rows
+}
+
+// レビューのエクスポートサービス
+func (s Service) ExportReviews(c *gin.Context) (*ReviewExport, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return nil, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return nil, http.StatusNotFound, err
+	}
+
+	// formatパラメータ取得、指定されてない場合はcsvを設定
+	export := ReviewExport{format: c.DefaultQuery("format", exportFormatCSV), db: db}
+	switch export.format {
+	case exportFormatCSV:
+		export.ContentType = "text/csv; charset=utf-8"
+		export.FileName = "reviews.csv"
+	case exportFormatJSON:
+		export.ContentType = "application/json; charset=utf-8"
+		export.FileName = "reviews.json"
+	case exportFormatMarkdown:
+		// 年ごとに1つのMarkdownファイルを作成し、zipにまとめる
+		export.ContentType = "application/zip"
+		export.FileName = "reviews.zip"
+	default:
+		return nil, http.StatusBadRequest, fmt.Errorf("format must be one of csv, json or md")
+	}
+
+	// ユーザIDをキーに、レビューを取得(クエリパラメータが指定されている場合は絞り込む)
+	// 読書完了日が無いレビューは、登録日の年(ユーザのタイムゾーンでの年)に含める
+	query := filterExportReviews(c, db.Model(&Review{}).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID))
+	rows, err := query.Select(responseReviewColumns+", extract(year from case when reviews.finish_read_at > '0001-01-01' then reviews.finish_read_at else to_timestamp(reviews.created_at) at time zone ? end)::int as year", userLocation(user).String()).Order("year, reviews.finish_read_at, reviews.id").Rows()
+	if err != nil {
+		// SELECT [responseReviewColumns],
+		//   extract(year from case when reviews.finish_read_at > '0001-01-01' then reviews.finish_read_at else to_timestamp(reviews.created_at) at time zone [ユーザのタイムゾーン] end)::int as year
+		// FROM `reviews` join `books` on reviews.book_id = books.id
+		// WHERE reviews.user_id = user.ID
+		//   [AND reviews.tags ILIKE %[tag]%] [AND reviews.reading_status = [readingStatus]]
+		//   [AND reviews.book_id IN (SELECT book_id FROM book_genres join genres ... WHERE genres.slug = [genre])]
+		// ORDER BY year, reviews.finish_read_at, reviews.id
+		return nil, http.StatusNotFound, err
+	}
+	export.rows = rows
+
+	return &export, http.StatusOK, nil
+}
+
+// エクスポート結果を、指定の形式でwに逐次書き出す
+func (e *ReviewExport) Stream(w io.Writer) error {
+	defer e.rows.Close()
+
+	switch e.format {
+	case exportFormatJSON:
+		return e.streamJSON(w)
+	case exportFormatMarkdown:
+		return e.streamMarkdown(w)
+	default:
+		return e.streamCSV(w)
+	}
+}
+
+// 次のレビューを読み込む(読み込むレビューが無い場合はfalseを返す)
+func (e *ReviewExport) next(review *exportReview) (bool, error) {
+	if !e.rows.Next() {
+		return false, e.rows.Err()
+	}
+
+	*review = exportReview{}
+	if err := e.db.ScanRows(e.rows, review); err != nil {
+		return false, err
+	}
+	// 読書開始日、完了日が0001-01-01の場合は空文字を格納する
+	review.StartReadAt = timeStrCoalesce(review.StartReadAt, "")
+	review.FinishReadAt = timeStrCoalesce(review.FinishReadAt, "")
+
+	return true, nil
+}
+
+// CSV形式で書き出す
+func (e *ReviewExport) streamCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	var review exportReview
+	for count := 1; ; count++ {
+		ok, err := e.next(&review)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		if err := writer.Write([]string{
+			strconv.FormatUint(uint64(review.ID), 10),
+			review.BookTitle,
+			review.BookAuthor,
+			review.BookPublishedDate,
+			strconv.FormatUint(uint64(review.BookNumOfPages), 10),
+			review.ReadingStatus,
+			strconv.FormatFloat(review.Rating, 'f', 1, 64),
+			strconv.FormatUint(uint64(review.ReadPages), 10),
+			review.StartReadAt,
+			review.FinishReadAt,
+			review.Tags,
+			review.Comment,
+		}); err != nil {
+			return err
+		}
+
+		if count%exportFlushInterval == 0 {
+			writer.Flush()
+			flush(w)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// JSON形式(レビューの配列)で書き出す
+func (e *ReviewExport) streamJSON(w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	var review exportReview
+	for count := 1; ; count++ {
+		ok, err := e.next(&review)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		if count > 1 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(review.ResponseReview)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		if count%exportFlushInterval == 0 {
+			flush(w)
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// Markdown形式で、年ごとに1ファイルとしてzipに書き出す
+func (e *ReviewExport) streamMarkdown(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	var review exportReview
+	var document io.Writer
+	currentYear := -1
+	for count := 1; ; count++ {
+		ok, err := e.next(&review)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		// 年が変わったら新しいファイルを作成
+		if review.Year != currentYear {
+			currentYear = review.Year
+			document, err = archive.Create(fmt.Sprintf("reviews-%04d.md", currentYear))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(document, "# Reading log %04d\n", currentYear); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(document, markdownReview(review.ResponseReview)); err != nil {
+			return err
+		}
+
+		if count%exportFlushInterval == 0 {
+			if err := archive.Flush(); err != nil {
+				return err
+			}
+			flush(w)
+		}
+	}
+
+	return archive.Close()
+}
+
+// レビュー1件分のMarkdownを生成する
+func markdownReview(review entity.ResponseReview) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## %s\n\n", review.BookTitle)
+	fmt.Fprintf(&b, "- Author: %s\n", review.BookAuthor)
+	if review.BookPublishedDate != "" {
+		fmt.Fprintf(&b, "- Published: %s\n", review.BookPublishedDate)
+	}
+	if review.BookNumOfPages > 0 {
+		fmt.Fprintf(&b, "- Pages: %d\n", review.BookNumOfPages)
+	}
+	fmt.Fprintf(&b, "- Status: %s\n", review.ReadingStatus)
+	if review.Rating > 0 {
+		fmt.Fprintf(&b, "- Rating: %s (%.1f)\n", ratingStars(review.Rating), review.Rating)
+	}
+	if review.StartReadAt != "" {
+		fmt.Fprintf(&b, "- Started: %s\n", review.StartReadAt)
+	}
+	if review.FinishReadAt != "" {
+		fmt.Fprintf(&b, "- Finished: %s\n", review.FinishReadAt)
+	}
+	if review.Tags != "" {
+		fmt.Fprintf(&b, "- Tags: %s\n", strings.ReplaceAll(review.Tags, ",", ", "))
+	}
+	if review.Comment != "" {
+		fmt.Fprintf(&b, "\n%s\n", review.Comment)
+	}
+	return b.String()
+}
+
+// 評価を5段階の星で表す(0.5以上の端数は切り上げる)
+func ratingStars(rating float64) string {
+	stars := int(rating + 0.5)
+	if stars > 5 {
+		stars = 5
+	}
+	return strings.Repeat("★", stars) + strings.Repeat("☆", 5-stars)
+}
+
+// wがhttp.Flusherを実装している場合は、バッファの内容をクライアントへ送信する
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// エクスポートするレビューを、クエリパラメータに応じて絞り込む
+// 一覧取得と同じジャンルに加え、タグ(部分一致)と読書ステータスで絞り込める
+func filterExportReviews(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if tag := c.Query("tag"); tag != "" {
+		query = query.Where("reviews.tags ILIKE ?", fmt.Sprintf("%%%s%%", tag))
+	}
+	if readingStatus := c.Query("readingStatus"); readingStatus != "" {
+		query = query.Where("reviews.reading_status = ?", readingStatus)
+	}
+	return filterReviewsByGenre(c, query)
+}
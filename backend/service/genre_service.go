@@ -0,0 +1,158 @@
+package service
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Genre entity.Genre
+
+// ジャンルの定義
+// 書籍メタデータプロバイダの分類(例: Google Booksの"Fiction / Mystery & Detective / General"、
+// Open Libraryの"Detective and mystery stories")に、キーワードのいずれかが含まれる場合にそのジャンルとする
+type genreDefinition struct {
+	Slug     string
+	Name     string
+	Keywords []string
+	Excludes []string // 判定前に分類から取り除く語句(例: "science fiction"を自然科学と判定しないため)
+}
+
+// ジャンルの一覧(1冊の書籍が複数のジャンルに該当してもよい)
+var genreDefinitions = []genreDefinition{
+	{Slug: "literature", Name: "文学・小説", Keywords: []string{"fiction", "literature", "novel", "novels", "小説", "文学"}, Excludes: []string{"nonfiction", "non-fiction"}},
+	{Slug: "mystery", Name: "ミステリー", Keywords: []string{"mystery", "detective", "crime", "thriller", "thrillers", "suspense", "ミステリー", "推理"}},
+	{Slug: "science-fiction", Name: "SF", Keywords: []string{"science fiction", "sci-fi", "ＳＦ", "SF"}},
+	{Slug: "fantasy", Name: "ファンタジー", Keywords: []string{"fantasy", "ファンタジー"}},
+	{Slug: "romance", Name: "恋愛", Keywords: []string{"romance", "love stories", "恋愛"}},
+	{Slug: "horror", Name: "ホラー", Keywords: []string{"horror", "ghost stories", "ホラー", "怪談"}},
+	{Slug: "comics", Name: "漫画", Keywords: []string{"comics", "graphic novels", "manga", "漫画", "コミック"}},
+	{Slug: "children", Name: "児童書・絵本", Keywords: []string{"juvenile", "children", "children's", "picture books", "児童", "絵本"}},
+	{Slug: "poetry", Name: "詩歌", Keywords: []string{"poetry", "poems", "詩", "短歌", "俳句"}},
+	{Slug: "business", Name: "ビジネス・経済", Keywords: []string{"business", "economics", "management", "finance", "ビジネス", "経済", "経営"}},
+	{Slug: "computers", Name: "コンピュータ・IT", Keywords: []string{"computers", "computer science", "programming", "software", "コンピュータ", "プログラミング"}},
+	{Slug: "science", Name: "自然科学", Keywords: []string{"science", "mathematics", "physics", "chemistry", "biology", "nature", "科学", "数学", "物理"}, Excludes: []string{"science fiction", "social science", "social sciences", "political science", "computer science"}},
+	{Slug: "social-science", Name: "社会・政治", Keywords: []string{"social science", "social sciences", "political science", "politics", "law", "education", "社会", "政治", "法律", "教育"}},
+	{Slug: "history", Name: "歴史", Keywords: []string{"history", "歴史"}},
+	{Slug: "biography", Name: "伝記・自伝", Keywords: []string{"biography", "autobiography", "memoir", "memoirs", "伝記", "自伝"}},
+	{Slug: "philosophy", Name: "哲学・思想", Keywords: []string{"philosophy", "哲学", "思想"}},
+	{Slug: "religion", Name: "宗教", Keywords: []string{"religion", "宗教"}},
+	{Slug: "self-help", Name: "自己啓発", Keywords: []string{"self-help", "自己啓発"}},
+	{Slug: "health", Name: "健康・医学", Keywords: []string{"health", "fitness", "medical", "medicine", "健康", "医学"}},
+	{Slug: "cooking", Name: "料理", Keywords: []string{"cooking", "cookbooks", "料理"}},
+	{Slug: "travel", Name: "旅行", Keywords: []string{"travel", "旅行"}},
+	{Slug: "art", Name: "芸術・デザイン", Keywords: []string{"art", "music", "photography", "design", "architecture", "芸術", "音楽", "デザイン", "建築"}},
+}
+
+// ジャンルごとの、キーワードに一致する正規表現
+// 英語のキーワードは単語単位で判定する(例: "art"が"heart"に一致しないようにする)
+var genrePatterns = compileGenrePatterns()
+
+// ジャンル一覧取得サービス
+func (s Service) GetGenres(c *gin.Context) ([]entity.ResponseGenre, StatusCode, error) {
+	db := db.GetDB()
+	genres := []entity.ResponseGenre{}
+	if err := db.Model(&Genre{}).Select("id, slug, name").Order("id").Scan(&genres).Error; err != nil {
+		return []entity.ResponseGenre{}, http.StatusInternalServerError, err
+	}
+	return genres, http.StatusOK, nil
+}
+
+// ジャンルを登録し、ジャンルが登録されていない書籍について、分類からジャンルを登録する
+func MigrateGenres() error {
+	db := db.GetDB()
+
+	// ジャンルの一覧を登録(登録済みの場合は名称を更新)
+	for _, definition := range genreDefinitions {
+		genre := Genre{Slug: definition.Slug, Name: definition.Name}
+		if err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"})}).Create(&genre).Error; err != nil {
+			return err
+		}
+	}
+
+	var books []Book
+	if err := db.Where("coalesce(categories, '') <> '' AND NOT EXISTS (SELECT 1 FROM book_genres WHERE book_genres.book_id = books.id)").Find(&books).Error; err != nil {
+		return err
+	}
+	for _, book := range books {
+		if err := assignGenres(db, book); err != nil {
+			return err
+		}
+	}
+	if len(books) > 0 {
+		log.Printf("migrated genres of %d books", len(books))
+	}
+	return nil
+}
+
+// 書籍の分類から判定したジャンルを、書籍に関連付ける
+func assignGenres(tx *gorm.DB, book Book) error {
+	slugs := genreSlugs(splitTags(book.Categories))
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	var genres []Genre
+	if err := tx.Where("slug IN ?", slugs).Find(&genres).Error; err != nil {
+		return err
+	}
+	for _, genre := range genres {
+		bookGenre := entity.BookGenre{BookID: book.ID, GenreID: genre.ID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookGenre).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 書籍メタデータプロバイダの分類から、該当するジャンルのスラッグを返す
+func genreSlugs(categories []string) []string {
+	var slugs []string
+	for i, definition := range genreDefinitions {
+		for _, category := range categories {
+			category = strings.ToLower(category)
+			for _, exclude := range definition.Excludes {
+				category = strings.ReplaceAll(category, strings.ToLower(exclude), " ")
+			}
+			if genrePatterns[i].MatchString(category) {
+				slugs = append(slugs, definition.Slug)
+				break
+			}
+		}
+	}
+	return slugs
+}
+
+// 書籍のジャンルを取得する
+func getBookGenres(tx *gorm.DB, bookID uint) ([]entity.ResponseGenre, error) {
+	genres := []entity.ResponseGenre{}
+	if err := tx.Model(&entity.BookGenre{}).Select("genres.id, genres.slug, genres.name").Joins("join genres on genres.id = book_genres.genre_id").Where("book_genres.book_id = ?", bookID).Order("genres.id").Scan(&genres).Error; err != nil {
+		// SELECT genres.id, genres.slug, genres.name
+		// FROM book_genres join genres on genres.id = book_genres.genre_id
+		// WHERE book_genres.book_id = [書籍ID]
+		// ORDER BY genres.id
+		return nil, err
+	}
+	return genres, nil
+}
+
+// ジャンルの定義から、キーワードに一致する正規表現を生成する
+func compileGenrePatterns() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(genreDefinitions))
+	for _, definition := range genreDefinitions {
+		var alternatives []string
+		for _, keyword := range definition.Keywords {
+			alternatives = append(alternatives, regexp.QuoteMeta(strings.ToLower(keyword)))
+		}
+		// 英数字以外(日本語を含む)で区切られた位置でのみ一致させる
+		patterns = append(patterns, regexp.MustCompile(`(^|[^a-z0-9'-])(`+strings.Join(alternatives, "|")+`)($|[^a-z0-9'-])`))
+	}
+	return patterns
+}
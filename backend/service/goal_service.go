@@ -0,0 +1,427 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type Goal entity.Goal
+type CreateGoalRequest entity.CreateGoalRequest
+type UpdateGoalRequest entity.UpdateGoalRequest
+
+// 予定どおりとみなす、見込まれる進捗との差の範囲(目標に対する割合)
+const goalOnTrackTolerance = 0.05
+
+// 読書目標の進捗の計算に用いる、読了したレビュー
+type goalFinish struct {
+	FinishReadAt time.Time
+	NumOfPages   int64
+}
+
+// 読書目標一覧取得サービス
+// ?active=trueの場合は、今日を期間に含む読書目標のみを返す
+func (s Service) GetGoals(c *gin.Context) ([]entity.GoalResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.GoalResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.GoalResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	// SELECT * FROM goals WHERE user_id = ? [AND start_date <= [今日] AND end_date >= [今日]] ORDER BY start_date desc, id;
+	today := userToday(user)
+	query := db.Where("user_id = ?", user.ID)
+	if active, _ := strconv.ParseBool(c.Query("active")); active {
+		query = query.Where("start_date <= ? AND end_date >= ?", today, today)
+	}
+	var goals []Goal
+	if err := query.Order("start_date desc, id").Find(&goals).Error; err != nil {
+		return []entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+
+	response := []entity.GoalResponse{}
+	for _, goal := range goals {
+		goalResponse, err := toGoalResponse(db, goal, today)
+		if err != nil {
+			return []entity.GoalResponse{}, http.StatusInternalServerError, err
+		}
+		response = append(response, goalResponse)
+	}
+	return response, http.StatusOK, nil
+}
+
+// 読書目標取得サービス
+func (s Service) GetGoal(c *gin.Context) (entity.GoalResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var goal Goal
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.GoalResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.GoalResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	// ログインユーザの読書目標を取得
+	if err := db.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&goal).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	response, err := toGoalResponse(db, goal, userToday(user))
+	if err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+// 読書目標登録サービス
+// 同じ指標、期間の読書目標は1件のみ登録できる
+func (s Service) CreateGoal(c *gin.Context) (entity.GoalResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request CreateGoalRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.GoalResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.GoalResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.GoalResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.GoalResponse{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	// 期間の開始日、終了日を決定
+	today := userToday(user)
+	startDate, endDate, err := goalDateRange(request, today)
+	if err != nil {
+		return entity.GoalResponse{}, http.StatusBadRequest, err
+	}
+
+	// 同じ指標、期間の読書目標が登録済みの場合はエラー
+	var numOfGoals int64
+	if err := db.Model(&Goal{}).Where("user_id = ? AND metric = ? AND start_date = ? AND end_date = ?", user.ID, request.Metric, startDate, endDate).Count(&numOfGoals).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+	if numOfGoals > 0 {
+		return entity.GoalResponse{}, http.StatusConflict, errors.New("goal for the same metric and period already exists")
+	}
+
+	newGoal := Goal{
+		Title:     strings.TrimSpace(request.Title),
+		Metric:    request.Metric,
+		Period:    request.Period,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Target:    request.Target,
+		UserID:    user.ID,
+	}
+	if err := db.Create(&newGoal).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+
+	response, err := toGoalResponse(db, newGoal, today)
+	if err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+	return response, http.StatusCreated, nil
+}
+
+// 読書目標更新サービス
+// タイトルと目標値のみ更新できる(期間を変更する場合は、削除して登録し直す)
+func (s Service) UpdateGoal(c *gin.Context) (entity.GoalResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var goal Goal
+	var request UpdateGoalRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.GoalResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.GoalResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.GoalResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.GoalResponse{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	// ログインユーザの読書目標を取得
+	if err := db.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&goal).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusNotFound, err
+	}
+
+	if request.Title != nil {
+		goal.Title = strings.TrimSpace(*request.Title)
+	}
+	if request.Target != nil {
+		goal.Target = *request.Target
+	}
+	if err := db.Model(&goal).Select("title", "target", "updated_at").Updates(&goal).Error; err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+
+	response, err := toGoalResponse(db, goal, userToday(user))
+	if err != nil {
+		return entity.GoalResponse{}, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+// 読書目標削除サービス
+func (s Service) DeleteGoal(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var goal Goal
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// ログインユーザの読書目標を取得
+	if err := db.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&goal).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	if err := db.Delete(&goal).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// 読書目標をレスポンス用構造体に変換する
+// 進捗は、期間内に読了日があり読書ステータスが読了のレビューから計算する
+func toGoalResponse(db *gorm.DB, goal Goal, today time.Time) (entity.GoalResponse, error) {
+	start, end := goalPeriodRange(goal.StartDate, goal.EndDate)
+	var finishes []goalFinish
+	if err := db.Model(&Review{}).Select("reviews.finish_read_at, books.num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.reading_status = ? AND reviews.finish_read_at >= ? AND reviews.finish_read_at < ?", goal.UserID, entity.ReadingStatusFinish, start, end).Order("reviews.finish_read_at, reviews.id").Scan(&finishes).Error; err != nil {
+		// SELECT reviews.finish_read_at, books.num_of_pages FROM reviews JOIN books ON reviews.book_id = books.id
+		// WHERE reviews.user_id = [goal.UserID] AND reviews.reading_status = 'Finish' AND reviews.finish_read_at >= [goal.StartDate] AND reviews.finish_read_at < [goal.EndDateの翌日]
+		// ORDER BY reviews.finish_read_at, reviews.id;
+		return entity.GoalResponse{}, err
+	}
+
+	response := entity.GoalResponse{
+		ID:        goal.ID,
+		Title:     goal.Title,
+		Metric:    goal.Metric,
+		Period:    goal.Period,
+		StartDate: formatDate(goal.StartDate),
+		EndDate:   formatDate(goal.EndDate),
+		Target:    goal.Target,
+		Progress:  calculateGoalProgress(goal, finishes, today),
+		CreatedAt: goal.CreatedAt,
+	}
+	return response, nil
+}
+
+// 読書目標の進捗を計算する
+// 期間の日数に対する経過日数の割合から今日までに見込まれる進捗を求め、実際の進捗と比較する
+func calculateGoalProgress(goal Goal, finishes []goalFinish, today time.Time) entity.GoalProgress {
+	progress := entity.GoalProgress{
+		TotalDays: daysBetween(goal.StartDate, goal.EndDate) + 1,
+	}
+
+	// 読了したレビューを読了日順に積み上げ、目標に達した日を達成日とする
+	for _, finish := range finishes {
+		if goal.Metric == entity.GoalMetricPages {
+			progress.Current += finish.NumOfPages
+		} else {
+			progress.Current++
+		}
+		if progress.CompletedDate == "" && progress.Current >= int64(goal.Target) {
+			progress.CompletedDate = formatDate(finish.FinishReadAt)
+		}
+	}
+	progress.Percentage = roundProgress(float64(progress.Current) / float64(goal.Target) * 100)
+
+	switch {
+	case today.Before(goal.StartDate):
+		progress.ElapsedDays = 0
+	case today.After(goal.EndDate):
+		progress.ElapsedDays = progress.TotalDays
+	default:
+		progress.ElapsedDays = daysBetween(goal.StartDate, today) + 1
+	}
+	expected := float64(goal.Target) * float64(progress.ElapsedDays) / float64(progress.TotalDays)
+	progress.Expected = roundProgress(expected)
+	progress.Difference = roundProgress(float64(progress.Current) - expected)
+
+	// 現在のペース(1日あたりの進捗)から、期間終了時の見込みと達成見込み日(期間中のみ)を求める
+	if progress.ElapsedDays > 0 {
+		pace := float64(progress.Current) / float64(progress.ElapsedDays)
+		progress.ProjectedTotal = roundProgress(pace * float64(progress.TotalDays))
+		if progress.CompletedDate == "" && pace > 0 && !today.After(goal.EndDate) {
+			days := int(math.Ceil(float64(goal.Target) / pace))
+			progress.ProjectedCompletionDate = goal.StartDate.AddDate(0, 0, days-1).Format(dateLayout)
+		}
+	}
+
+	tolerance := float64(goal.Target) * goalOnTrackTolerance
+	switch {
+	case progress.CompletedDate != "":
+		progress.Status = entity.GoalStatusCompleted
+	case today.After(goal.EndDate):
+		progress.Status = entity.GoalStatusFailed
+	case today.Before(goal.StartDate):
+		progress.Status = entity.GoalStatusNotStarted
+	case float64(progress.Current)-expected > tolerance:
+		progress.Status = entity.GoalStatusAhead
+	case expected-float64(progress.Current) > tolerance:
+		progress.Status = entity.GoalStatusBehind
+	default:
+		progress.Status = entity.GoalStatusOnTrack
+	}
+	return progress
+}
+
+// 読書目標の期間の開始日、終了日を返す
+func goalDateRange(request CreateGoalRequest, today time.Time) (time.Time, time.Time, error) {
+	year, month := request.Year, request.Month
+	if year == 0 {
+		year = today.Year()
+	}
+	if month == 0 {
+		month = int(today.Month())
+	}
+
+	switch request.Period {
+	case entity.GoalPeriodYear:
+		startDate, endDate := yearRange(year)
+		return startDate, endDate.AddDate(0, 0, -1), nil
+	case entity.GoalPeriodMonth:
+		startDate, endDate := monthRange(year, time.Month(month))
+		return startDate, endDate.AddDate(0, 0, -1), nil
+	}
+
+	// 期間を指定する場合は、開始日と終了日が必須
+	if request.StartDate == "" || request.EndDate == "" {
+		return time.Time{}, time.Time{}, errors.New("startDate and endDate are required for custom period")
+	}
+	startDate, err := time.Parse(dateLayout, request.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endDate, err := time.Parse(dateLayout, request.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("endDate must not be before startDate")
+	}
+	return startDate, endDate, nil
+}
+
+// 読書目標の期間(終了日を含む)を、終了日の翌日までの半開区間[start, end)で返す
+// 終了日の0時以降の読了日も含まれるようにする
+func goalPeriodRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	return startDate, endDate.AddDate(0, 0, 1)
+}
+
+// 進捗の値を小数点以下2桁に丸める
+func roundProgress(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// 2つの日付の間の日数を返す
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type ImportJob entity.ImportJob
+type ImportJobRow entity.ImportJobRow
+type ResponseImportJob entity.ResponseImportJob
+
+// インポート対象のレビュー1件分のデータ
+type importReviewRecord struct {
+	RowNumber     uint
+	Title         string
+	Author        string
+	ISBN10        string
+	ISBN13        string
+	PublishedDate string
+	NumOfPages    uint
+	Rating        float64
+	ReadingStatus string
+	ReadPages     uint
+	FinishReadAt  time.Time
+	Comment       string
+	Tags          string
+	Err           error // 行の解析時に発生したエラー
+}
+
+// Goodreadsのエクスポートファイルに必須の列
+var goodreadsRequiredColumns = []string{"Title", "Author", "Exclusive Shelf"}
+
+// Goodreadsの棚名と読書ステータスの対応
+var goodreadsShelfToReadingStatus = map[string]string{
+	"read":              entity.ReadingStatusFinish,
+	"currently-reading": entity.ReadingStatusReading,
+	"to-read":           entity.ReadingStatusToRead,
+}
+
+// GoodreadsのCSVインポートサービス
+func (s Service) ImportGoodreads(c *gin.Context) (ResponseImportJob, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return ResponseImportJob{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return ResponseImportJob{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return ResponseImportJob{}, http.StatusNotFound, err
+	}
+
+	// dryRunパラメータ取得、指定されてない場合はfalseを設定
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+
+	// アップロードされたCSVファイルを解析
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+	defer file.Close()
+
+	records, err := parseGoodreadsCSV(file)
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+
+	// インポートジョブを登録し、バックグラウンドで実行
+	job := ImportJob{
+		Source:    "goodreads",
+		Status:    entity.ImportJobStatusPending,
+		DryRun:    dryRun,
+		TotalRows: uint(len(records)),
+		UserID:    user.ID,
+	}
+	if err := db.Create(&job).Error; err != nil {
+		return ResponseImportJob{}, http.StatusInternalServerError, err
+	}
+
+	go runReviewImport(job.ID, user.ID, records, dryRun)
+
+	return toResponseImportJob(job, []ImportJobRow{}), http.StatusAccepted, nil
+}
+
+// インポートジョブ取得サービス
+func (s Service) GetImportJob(c *gin.Context) (ResponseImportJob, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return ResponseImportJob{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return ResponseImportJob{}, http.StatusForbidden, err
+	}
+
+	// IDをキーに、インポートジョブを取得
+	var job ImportJob
+	if err := db.Where("id = ?", c.Param("jobId")).First(&job).Error; err != nil {
+		return ResponseImportJob{}, http.StatusNotFound, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return ResponseImportJob{}, http.StatusNotFound, err
+	}
+
+	// 対象ジョブのユーザIDと、ログインユーザIDが一致していなければ取得しない
+	if job.UserID != user.ID {
+		return ResponseImportJob{}, http.StatusForbidden, fmt.Errorf("couldn't get this import job")
+	}
+
+	// ジョブIDをキーに、行単位の処理結果を取得
+	var rows []ImportJobRow
+	if err := db.Where("import_job_id = ?", job.ID).Order("row_number").Find(&rows).Error; err != nil {
+		return ResponseImportJob{}, http.StatusNotFound, err
+	}
+
+	return toResponseImportJob(job, rows), http.StatusOK, nil
+}
+
+// GoodreadsのエクスポートCSVを解析し、インポート対象のレビューデータに変換する
+func parseGoodreadsCSV(r io.Reader) ([]importReviewRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read csv header: %w", err)
+	}
+
+	// 列名と列番号の対応を作成(BOM付きファイルを考慮する)
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range goodreadsRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%q column is missing, this is not a Goodreads export file", name)
+		}
+	}
+
+	var records []importReviewRecord
+	for rowNumber := uint(2); ; rowNumber++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			records = append(records, importReviewRecord{RowNumber: rowNumber, Err: err})
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		records = append(records, parseGoodreadsRow(rowNumber, get))
+	}
+
+	return records, nil
+}
+
+// Goodreadsのエクスポートファイル1行分を、インポート対象のレビューデータに変換する
+func parseGoodreadsRow(rowNumber uint, get func(string) string) importReviewRecord {
+	record := importReviewRecord{
+		RowNumber: rowNumber,
+		Title:     get("Title"),
+		Author:    get("Author"),
+		ISBN10:    trimGoodreadsISBN(get("ISBN")),
+		ISBN13:    trimGoodreadsISBN(get("ISBN13")),
+		Comment:   strings.NewReplacer("<br/>", "\n", "<br />", "\n").Replace(get("My Review")),
+	}
+
+	// ISBNは、ISBN-10とISBN-13の組に正規化する(不正なISBNは取り込まない)
+	record.ISBN10, record.ISBN13 = normalizeISBNPair(record.ISBN10, record.ISBN13)
+
+	// 共著者がいる場合は、書籍検索と同様にカンマで区切る
+	if additionalAuthors := get("Additional Authors"); additionalAuthors != "" {
+		record.Author = strings.Join([]string{record.Author, additionalAuthors}, ", ")
+	}
+	if record.Title == "" || record.Author == "" {
+		record.Err = fmt.Errorf("title and author are required")
+		return record
+	}
+
+	// 出版年は、初版の出版年を優先する
+	record.PublishedDate = get("Original Publication Year")
+	if record.PublishedDate == "" {
+		record.PublishedDate = get("Year Published")
+	}
+
+	if numOfPages := get("Number of Pages"); numOfPages != "" {
+		n, err := strconv.ParseUint(numOfPages, 10, 32)
+		if err != nil {
+			record.Err = fmt.Errorf("invalid number of pages %q", numOfPages)
+			return record
+		}
+		record.NumOfPages = uint(n)
+	}
+
+	// 評価0は未評価を表す
+	if rating := get("My Rating"); rating != "" {
+		r, err := strconv.ParseFloat(rating, 64)
+		if err != nil || r < 0 || r > 5 {
+			record.Err = fmt.Errorf("invalid rating %q", rating)
+			return record
+		}
+		record.Rating = r
+	}
+
+	if dateRead := get("Date Read"); dateRead != "" {
+		finishReadAt, err := time.Parse("2006/01/02", dateRead)
+		if err != nil {
+			record.Err = fmt.Errorf("invalid date read %q", dateRead)
+			return record
+		}
+		record.FinishReadAt = finishReadAt
+	}
+
+	// 排他的な棚を読書ステータスに、それ以外の棚をタグに変換
+	exclusiveShelf := get("Exclusive Shelf")
+	readingStatus, ok := goodreadsShelfToReadingStatus[exclusiveShelf]
+	if !ok {
+		readingStatus = entity.ReadingStatusToRead
+	}
+	record.ReadingStatus = readingStatus
+	if readingStatus == entity.ReadingStatusFinish {
+		record.ReadPages = record.NumOfPages
+	}
+
+	var tags []string
+	for _, shelf := range strings.Split(get("Bookshelves"), ",") {
+		shelf = strings.TrimSpace(shelf)
+		if _, isStatus := goodreadsShelfToReadingStatus[shelf]; shelf == "" || isStatus {
+			continue
+		}
+		tags = append(tags, shelf)
+	}
+	if _, isStatus := goodreadsShelfToReadingStatus[exclusiveShelf]; !isStatus && exclusiveShelf != "" && !containsString(tags, exclusiveShelf) {
+		tags = append(tags, exclusiveShelf)
+	}
+	record.Tags = strings.Join(tags, ",")
+
+	return record
+}
+
+// Goodreadsのエクスポートファイルに含まれる ="0123456789" 形式のISBNから、数字部分を取り出す
+func trimGoodreadsISBN(isbn string) string {
+	return strings.Trim(strings.TrimPrefix(isbn, "="), "\"")
+}
+
+// Goodreadsのインポートジョブを実行する
+func runReviewImport(jobID, userID uint, records []importReviewRecord, dryRun bool) {
+	runImportJob(jobID, func(db *gorm.DB, addRow func(ImportJobRow)) {
+		// 同一ファイル内で重複する書籍は、2件目以降をスキップする
+		seen := map[string]bool{}
+		for _, record := range records {
+			row := ImportJobRow{
+				RowNumber: record.RowNumber,
+				Title:     record.Title,
+				Author:    record.Author,
+			}
+
+			key := record.Title + "\x00" + record.Author
+			switch {
+			case record.Err != nil:
+				row.Action = entity.ImportRowActionError
+				row.Message = record.Err.Error()
+			case seen[key]:
+				row.Action = entity.ImportRowActionSkipped
+				row.Message = "duplicated in the imported file"
+			default:
+				action, err := importReview(db, userID, record, dryRun)
+				row.Action = action
+				if err != nil {
+					row.Message = err.Error()
+				}
+			}
+			seen[key] = true
+
+			addRow(row)
+		}
+	})
+}
+
+// インポートジョブを実行し、processから渡される行単位の処理結果を記録する
+func runImportJob(jobID uint, process func(db *gorm.DB, addRow func(ImportJobRow))) {
+	db := db.GetDB()
+
+	var job ImportJob
+	if err := db.Where("id = ?", jobID).First(&job).Error; err != nil {
+		log.Println(err)
+		return
+	}
+
+	// 予期せぬエラーが発生した場合はジョブを失敗として記録
+	defer func() {
+		if r := recover(); r != nil {
+			job.Status = entity.ImportJobStatusFailed
+			job.ErrorMessage = fmt.Sprint(r)
+			if err := db.Save(&job).Error; err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+
+	job.Status = entity.ImportJobStatusRunning
+	if err := db.Save(&job).Error; err != nil {
+		log.Println(err)
+		return
+	}
+
+	process(db, func(row ImportJobRow) {
+		switch row.Action {
+		case entity.ImportRowActionCreated:
+			job.CreatedRows++
+		case entity.ImportRowActionSkipped:
+			job.SkippedRows++
+		default:
+			job.FailedRows++
+		}
+
+		row.ImportJobID = job.ID
+		if err := db.Create(&row).Error; err != nil {
+			log.Println(err)
+		}
+	})
+
+	job.Status = entity.ImportJobStatusDone
+	if err := db.Save(&job).Error; err != nil {
+		log.Println(err)
+	}
+}
+
+// レビュー1件をインポートし、処理結果を返す(dryRunの場合は登録を行わない)
+func importReview(db *gorm.DB, userID uint, record importReviewRecord, dryRun bool) (string, error) {
+	action := entity.ImportRowActionCreated
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// レビュー登録と同様に、ISBN、またはタイトルと著者で書籍を照合
+		identity := bookIdentity{Title: record.Title, Author: record.Author, ISBN10: record.ISBN10, ISBN13: record.ISBN13}
+		book, err := findBook(tx, identity)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err == nil {
+			// ユーザIDと書籍をキーに、同じ作品(いずれかの版)をレビュー済みか判定
+			_, err := findWorkReview(tx, userID, book)
+			if err == nil {
+				action = entity.ImportRowActionSkipped
+				return errors.New("already reviewed")
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		// Bookがデータベースに無い場合は新規登録
+		if book.ID == 0 {
+			book = Book{
+				Title:         record.Title,
+				Author:        record.Author,
+				PublishedDate: record.PublishedDate,
+				NumOfPages:    record.NumOfPages,
+				ISBN10:        record.ISBN10,
+				ISBN13:        record.ISBN13,
+			}
+			// 他の取り込みと同時に同じ書籍が登録された場合は、その書籍を用いる
+			if _, err := createOrFindBook(tx, &book, nil, 0, userID); err != nil {
+				return err
+			}
+		} else if err := fillBookIdentity(tx, &book, identity); err != nil {
+			// 登録済みのBookに無い識別子を補完
+			return err
+		}
+
+		newReview := Review{
+			Comment:       record.Comment,
+			Rating:        record.Rating,
+			ReadingStatus: record.ReadingStatus,
+			ReadPages:     record.ReadPages,
+			FinishReadAt:  record.FinishReadAt,
+			Tags:          record.Tags,
+			UserID:        userID,
+			BookID:        book.ID,
+		}
+		if err := tx.Create(&newReview).Error; err != nil {
+			return err
+		}
+		// 読了月の統計を更新
+		return refreshMonthlyReviewStats(tx, userID, newReview.FinishReadAt)
+	})
+
+	if err != nil && action != entity.ImportRowActionSkipped {
+		action = entity.ImportRowActionError
+	}
+	return action, err
+}
+
+// インポートジョブをレスポンス用構造体に変換する
+func toResponseImportJob(job ImportJob, rows []ImportJobRow) ResponseImportJob {
+	responseRows := []entity.ResponseImportJobRow{}
+	for _, row := range rows {
+		responseRows = append(responseRows, entity.ResponseImportJobRow{
+			RowNumber: row.RowNumber,
+			Title:     row.Title,
+			Author:    row.Author,
+			Action:    row.Action,
+			Message:   row.Message,
+		})
+	}
+
+	return ResponseImportJob{
+		ID:           job.ID,
+		Source:       job.Source,
+		Status:       job.Status,
+		DryRun:       job.DryRun,
+		TotalRows:    job.TotalRows,
+		CreatedRows:  job.CreatedRows,
+		SkippedRows:  job.SkippedRows,
+		FailedRows:   job.FailedRows,
+		ErrorMessage: job.ErrorMessage,
+		Rows:         responseRows,
+	}
+}
+
+// 文字列スライスに対象の文字列が含まれているか判定する
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
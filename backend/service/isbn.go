@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"strings"
+)
+
+// ISBNとして不正な場合のエラー
+var errInvalidISBN = errors.New("isbn must be a valid ISBN-10 or ISBN-13")
+
+// ISBNから、ハイフンと空白を取り除く(ISBN-10のチェックディジットXは大文字にする)
+func cleanISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn)))
+}
+
+// ISBN-10として正しいか(桁数とチェックディジット)を判定する
+func isValidISBN10(isbn string) bool {
+	if len(isbn) != 10 {
+		return false
+	}
+	sum := 0
+	for i, r := range isbn {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// ISBN-13として正しいか(桁数とチェックディジット)を判定する
+func isValidISBN13(isbn string) bool {
+	if len(isbn) != 13 || !isDigits(isbn) {
+		return false
+	}
+	return isbn13CheckDigit(isbn[:12]) == isbn[12]
+}
+
+// ISBN-13の先頭12桁から、チェックディジットを計算する
+func isbn13CheckDigit(isbn string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(isbn[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// ISBN-10の先頭9桁から、チェックディジットを計算する
+func isbn10CheckDigit(isbn string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(isbn[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// ISBN-10をISBN-13に変換する
+func isbn10To13(isbn string) string {
+	prefix := "978" + isbn[:9]
+	return prefix + string(isbn13CheckDigit(prefix))
+}
+
+// ISBN-13をISBN-10に変換する(978から始まらないISBN-13は変換できないため空文字を返す)
+func isbn13To10(isbn string) string {
+	if !strings.HasPrefix(isbn, "978") {
+		return ""
+	}
+	body := isbn[3:12]
+	return body + string(isbn10CheckDigit(body))
+}
+
+// ISBN-10、またはISBN-13を検証し、ISBN-10とISBN-13の組を返す
+// ISBN-10が存在しない(979から始まる)場合、ISBN-10は空文字となる
+func normalizeISBN(isbn string) (string, string, error) {
+	isbn = cleanISBN(isbn)
+	switch {
+	case isValidISBN10(isbn):
+		return isbn, isbn10To13(isbn), nil
+	case isValidISBN13(isbn):
+		return isbn13To10(isbn), isbn, nil
+	default:
+		return "", "", errInvalidISBN
+	}
+}
+
+// ISBN-10とISBN-13の組を正規化する(不正なISBNは空文字とする)
+func normalizeISBNPair(isbn10, isbn13 string) (string, string) {
+	if normalized10, normalized13, err := normalizeISBN(isbn13); err == nil {
+		return normalized10, normalized13
+	}
+	if normalized10, normalized13, err := normalizeISBN(isbn10); err == nil {
+		return normalized10, normalized13
+	}
+	return "", ""
+}
+
+// 文字列が数字のみで構成されているか判定する
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsValidISBN(t *testing.T) {
+	tests := []struct {
+		name   string
+		isbn   string
+		isbn10 bool
+		isbn13 bool
+	}{
+		{"正しいISBN-10", "0306406152", true, false},
+		{"チェックディジットが誤ったISBN-10", "0306406153", false, false},
+		{"チェックディジットがXのISBN-10", "080442957X", true, false},
+		{"X以外の文字を含むISBN-10", "08044295Y9", false, false},
+		{"末尾以外にXを含むISBN-10", "X804429573", false, false},
+		{"正しいISBN-13", "9780306406157", false, true},
+		{"チェックディジットが誤ったISBN-13", "9780306406158", false, false},
+		{"979から始まるISBN-13", "9791090636071", false, true},
+		{"桁数が足りない", "978030640615", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidISBN10(tt.isbn); got != tt.isbn10 {
+				t.Errorf("isValidISBN10(%q) = %v, want %v", tt.isbn, got, tt.isbn10)
+			}
+			if got := isValidISBN13(tt.isbn); got != tt.isbn13 {
+				t.Errorf("isValidISBN13(%q) = %v, want %v", tt.isbn, got, tt.isbn13)
+			}
+		})
+	}
+}
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name    string
+		isbn    string
+		want10  string
+		want13  string
+		wantErr bool
+	}{
+		{"ISBN-10", "0306406152", "0306406152", "9780306406157", false},
+		{"ISBN-13", "9780306406157", "0306406152", "9780306406157", false},
+		{"ハイフン区切り", "978-0-306-40615-7", "0306406152", "9780306406157", false},
+		{"空白区切りと前後の空白", " 0 306 40615 2 ", "0306406152", "9780306406157", false},
+		{"小文字のチェックディジットx", "0-8044-2957-x", "080442957X", "9780804429573", false},
+		{"ISBN-13からチェックディジットがXのISBN-10へ変換", "9780804429573", "080442957X", "9780804429573", false},
+		{"979から始まるISBN-13はISBN-10が無い", "979-10-90636-07-1", "", "9791090636071", false},
+		{"チェックディジットが誤ったISBN", "978-0-306-40615-8", "", "", true},
+		{"空文字", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got10, got13, err := normalizeISBN(tt.isbn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeISBN(%q) error = %v, wantErr %v", tt.isbn, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidISBN) {
+				t.Errorf("normalizeISBN(%q) error = %v, want errInvalidISBN", tt.isbn, err)
+			}
+			if got10 != tt.want10 || got13 != tt.want13 {
+				t.Errorf("normalizeISBN(%q) = %q, %q, want %q, %q", tt.isbn, got10, got13, tt.want10, tt.want13)
+			}
+		})
+	}
+}
+
+func TestISBNConversionRoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "080442957X", "4101092052"} {
+		isbn13 := isbn10To13(isbn10)
+		if !isValidISBN13(isbn13) {
+			t.Errorf("isbn10To13(%q) = %q, which is not a valid ISBN-13", isbn10, isbn13)
+		}
+		if got := isbn13To10(isbn13); got != isbn10 {
+			t.Errorf("isbn13To10(isbn10To13(%q)) = %q, want %q", isbn10, got, isbn10)
+		}
+	}
+
+	// 979から始まるISBN-13は、ISBN-10に変換できない
+	if got := isbn13To10("9791090636071"); got != "" {
+		t.Errorf("isbn13To10(979...) = %q, want empty", got)
+	}
+}
+
+func TestNormalizeISBNPair(t *testing.T) {
+	tests := []struct {
+		name           string
+		isbn10, isbn13 string
+		want10, want13 string
+	}{
+		{"ISBN-13を優先する", "0306406152", "9780804429573", "080442957X", "9780804429573"},
+		{"ISBN-13が不正な場合はISBN-10から求める", "0306406152", "9780306406158", "0306406152", "9780306406157"},
+		{"両方不正な場合は空文字", "0306406153", "9780306406158", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got10, got13 := normalizeISBNPair(tt.isbn10, tt.isbn13)
+			if got10 != tt.want10 || got13 != tt.want13 {
+				t.Errorf("normalizeISBNPair(%q, %q) = %q, %q, want %q, %q", tt.isbn10, tt.isbn13, got10, got13, tt.want10, tt.want13)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+// Kindleのクリッピングの種類
+const (
+	kindleClippingHighlight = "highlight"
+	kindleClippingNote      = "note"
+	kindleClippingBookmark  = "bookmark"
+)
+
+// My Clippings.txtのクリッピングの区切り行
+const kindleClippingSeparator = "=========="
+
+// 著者不明の書籍に設定する著者名
+const kindleUnknownAuthor = "Unknown"
+
+// My Clippings.txtのメタデータ行(英語版、日本語版)の解析用正規表現
+var (
+	kindleTitleAuthorPattern = regexp.MustCompile(`^(.*)\(([^()]*)\)$`)
+	kindleKindPatterns       = map[string]*regexp.Regexp{
+		kindleClippingHighlight: regexp.MustCompile(`Highlight|ハイライト`),
+		kindleClippingNote:      regexp.MustCompile(`Note|メモ`),
+		kindleClippingBookmark:  regexp.MustCompile(`Bookmark|ブックマーク`),
+	}
+	kindlePagePattern     = regexp.MustCompile(`(?:[Pp]age (\d+))|(?:(\d+)\s*ページ)`)
+	kindleLocationPattern = regexp.MustCompile(`(?:Location|Loc\.|位置No\.)\s*([\d-]+)`)
+	kindleAddedOnPattern  = regexp.MustCompile(`Added on (.+)$`)
+	kindleJapaneseDate    = regexp.MustCompile(`作成日:\s*(\d+)年(\d+)月(\d+)日\S*\s+(\d+):(\d+):(\d+)`)
+)
+
+// My Clippings.txtの英語版の日時フォーマット
+var kindleAddedOnLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, January 2, 2006, 3:04 PM",
+}
+
+// Kindleのクリッピング1件分のデータ
+type kindleClipping struct {
+	RowNumber uint
+	Title     string
+	Author    string
+	Kind      string
+	Page      uint
+	Location  string
+	AddedAt   time.Time
+	Text      string
+	Err       error // クリッピングの解析時に発生したエラー
+}
+
+// Kindleのハイライトインポートサービス
+func (s Service) ImportKindleClippings(c *gin.Context) (ResponseImportJob, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return ResponseImportJob{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return ResponseImportJob{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return ResponseImportJob{}, http.StatusNotFound, err
+	}
+
+	// dryRunパラメータ取得、指定されてない場合はfalseを設定
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+
+	// アップロードされたMy Clippings.txtを解析
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+	defer file.Close()
+
+	clippings, err := parseKindleClippings(file)
+	if err != nil {
+		return ResponseImportJob{}, http.StatusBadRequest, err
+	}
+
+	// インポートジョブを登録し、バックグラウンドで実行
+	job := ImportJob{
+		Source:    "kindle",
+		Status:    entity.ImportJobStatusPending,
+		DryRun:    dryRun,
+		TotalRows: uint(len(clippings)),
+		UserID:    user.ID,
+	}
+	if err := db.Create(&job).Error; err != nil {
+		return ResponseImportJob{}, http.StatusInternalServerError, err
+	}
+
+	go runKindleImport(job.ID, user.ID, clippings, dryRun)
+
+	return toResponseImportJob(job, []ImportJobRow{}), http.StatusAccepted, nil
+}
+
+// My Clippings.txtを解析し、クリッピングのスライスに変換する
+func parseKindleClippings(r io.Reader) ([]kindleClipping, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var clippings []kindleClipping
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r")
+		if strings.TrimSpace(line) != kindleClippingSeparator {
+			lines = append(lines, line)
+			continue
+		}
+
+		clippings = append(clippings, parseKindleClipping(uint(len(clippings)+1), lines))
+		lines = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(clippings) == 0 {
+		return nil, fmt.Errorf("no clippings found, this is not a Kindle \"My Clippings.txt\" file")
+	}
+
+	return clippings, nil
+}
+
+// クリッピング1件分(区切り行の間の行)を解析する
+func parseKindleClipping(rowNumber uint, lines []string) kindleClipping {
+	clipping := kindleClipping{RowNumber: rowNumber}
+
+	// 先頭の空行を除去
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		clipping.Err = fmt.Errorf("clipping must have a title line and a metadata line")
+		return clipping
+	}
+
+	// 1行目: "タイトル (著者)"
+	titleLine := strings.TrimSpace(lines[0])
+	if matches := kindleTitleAuthorPattern.FindStringSubmatch(titleLine); matches != nil {
+		clipping.Title = strings.TrimSpace(matches[1])
+		clipping.Author = strings.TrimSpace(matches[2])
+	} else {
+		clipping.Title = titleLine
+	}
+	if clipping.Author == "" {
+		clipping.Author = kindleUnknownAuthor
+	}
+
+	// 2行目: "- Your Highlight on page 12 | Location 123-125 | Added on ..."
+	metadata := lines[1]
+	for _, kind := range []string{kindleClippingHighlight, kindleClippingNote, kindleClippingBookmark} {
+		if kindleKindPatterns[kind].MatchString(metadata) {
+			clipping.Kind = kind
+			break
+		}
+	}
+	if clipping.Kind == "" {
+		clipping.Err = fmt.Errorf("unknown clipping type %q", metadata)
+		return clipping
+	}
+	if matches := kindlePagePattern.FindStringSubmatch(metadata); matches != nil {
+		page, _ := strconv.ParseUint(matches[1]+matches[2], 10, 32)
+		clipping.Page = uint(page)
+	}
+	if matches := kindleLocationPattern.FindStringSubmatch(metadata); matches != nil {
+		clipping.Location = matches[1]
+	}
+	addedAt, err := parseKindleAddedOn(metadata)
+	if err != nil {
+		clipping.Err = err
+		return clipping
+	}
+	clipping.AddedAt = addedAt
+
+	// 3行目以降: 本文(ブックマークの場合は空)
+	clipping.Text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if clipping.Kind != kindleClippingBookmark && clipping.Text == "" {
+		clipping.Err = fmt.Errorf("%s has no text", clipping.Kind)
+	}
+
+	return clipping
+}
+
+// メタデータ行から、クリッピングの作成日時を取得する
+func parseKindleAddedOn(metadata string) (time.Time, error) {
+	if matches := kindleJapaneseDate.FindStringSubmatch(metadata); matches != nil {
+		var values [6]int
+		for i := range values {
+			values[i], _ = strconv.Atoi(matches[i+1])
+		}
+		return time.Date(values[0], time.Month(values[1]), values[2], values[3], values[4], values[5], 0, time.UTC), nil
+	}
+
+	if matches := kindleAddedOnPattern.FindStringSubmatch(metadata); matches != nil {
+		for _, layout := range kindleAddedOnLayouts {
+			if addedAt, err := time.Parse(layout, strings.TrimSpace(matches[1])); err == nil {
+				return addedAt, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", matches[1])
+	}
+
+	// 作成日時が無い場合はエラーとしない
+	return time.Time{}, nil
+}
+
+// Kindleのインポートジョブを実行する
+func runKindleImport(jobID, userID uint, clippings []kindleClipping, dryRun bool) {
+	runImportJob(jobID, func(db *gorm.DB, addRow func(ImportJobRow)) {
+		// 書籍ごとにまとめて処理する(書籍の順序はファイル内の出現順)
+		var bookKeys []string
+		clippingsByBook := map[string][]kindleClipping{}
+		for _, clipping := range clippings {
+			if clipping.Err != nil {
+				addRow(ImportJobRow{
+					RowNumber: clipping.RowNumber,
+					Title:     clipping.Title,
+					Author:    clipping.Author,
+					Action:    entity.ImportRowActionError,
+					Message:   clipping.Err.Error(),
+				})
+				continue
+			}
+
+			key := clipping.Title + "\x00" + clipping.Author
+			if _, ok := clippingsByBook[key]; !ok {
+				bookKeys = append(bookKeys, key)
+			}
+			clippingsByBook[key] = append(clippingsByBook[key], clipping)
+		}
+
+		for _, key := range bookKeys {
+			for _, row := range importKindleBook(db, userID, clippingsByBook[key], dryRun) {
+				addRow(row)
+			}
+		}
+	})
+}
+
+// 1冊分のクリッピングをインポートし、クリッピングごとの処理結果を返す(dryRunの場合は登録を行わない)
+func importKindleBook(db *gorm.DB, userID uint, clippings []kindleClipping, dryRun bool) []ImportJobRow {
+	var rows []ImportJobRow
+	err := db.Transaction(func(tx *gorm.DB) error {
+		rows = nil
+		review, err := findOrCreateKindleReview(tx, userID, clippings, dryRun)
+		if err != nil {
+			return err
+		}
+
+		// ハイライトの終了位置と引用の対応(メモを同じ位置のハイライトに紐づけるため)
+		quotesByLocationEnd := map[string]*Quote{}
+		for _, clipping := range clippings {
+			row := ImportJobRow{
+				RowNumber: clipping.RowNumber,
+				Title:     clipping.Title,
+				Author:    clipping.Author,
+			}
+
+			switch clipping.Kind {
+			case kindleClippingBookmark:
+				row.Action = entity.ImportRowActionSkipped
+				row.Message = "bookmarks are not imported"
+			case kindleClippingHighlight:
+				quote, created, err := importKindleHighlight(tx, review, clipping, dryRun)
+				if err != nil {
+					return err
+				}
+				quotesByLocationEnd[kindleLocationEnd(clipping.Location)] = quote
+				if created {
+					row.Action = entity.ImportRowActionCreated
+				} else {
+					row.Action = entity.ImportRowActionSkipped
+					row.Message = "already imported"
+				}
+			case kindleClippingNote:
+				created, err := importKindleNote(tx, review, clipping, quotesByLocationEnd, dryRun)
+				if err != nil {
+					return err
+				}
+				if created {
+					row.Action = entity.ImportRowActionCreated
+				} else {
+					row.Action = entity.ImportRowActionSkipped
+					row.Message = "already imported"
+				}
+			}
+
+			rows = append(rows, row)
+		}
+
+		return nil
+	})
+
+	// 書籍単位で失敗した場合は、その書籍の全クリッピングをエラーとする
+	if err != nil {
+		rows = nil
+		for _, clipping := range clippings {
+			rows = append(rows, ImportJobRow{
+				RowNumber: clipping.RowNumber,
+				Title:     clipping.Title,
+				Author:    clipping.Author,
+				Action:    entity.ImportRowActionError,
+				Message:   err.Error(),
+			})
+		}
+	}
+
+	return rows
+}
+
+// クリッピングの書籍に対するユーザのレビューを取得する
+// 書籍、レビューが無い場合は新規作成する(レビューは読書中として登録する)
+func findOrCreateKindleReview(tx *gorm.DB, userID uint, clippings []kindleClipping, dryRun bool) (Review, error) {
+	title, author := clippings[0].Title, clippings[0].Author
+
+	// レビュー登録と同様に、タイトルと著者で書籍を照合
+	// 見つからない場合は、ユーザがレビュー済みの同じタイトルの書籍を探す
+	book, err := findBook(tx, bookIdentity{Title: title, Author: author})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = tx.Joins("join reviews on reviews.book_id = books.id").Where("reviews.user_id = ? AND books.title = ?", userID, title).First(&book).Error
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return Review{}, err
+	}
+
+	if book.ID == 0 {
+		if dryRun {
+			return Review{}, nil
+		}
+		book = Book{Title: title, Author: author}
+		if err := createBook(tx, &book, nil, 0, userID); err != nil {
+			return Review{}, err
+		}
+	}
+
+	// 同じ作品(いずれかの版)に対するレビューがある場合は、そのレビューに引用を登録する
+	review, err := findWorkReview(tx, userID, book)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return review, err
+	}
+	if dryRun {
+		return Review{}, nil
+	}
+
+	// 読書開始日は、最も古いクリッピングの日付とする
+	var startReadAt time.Time
+	for _, clipping := range clippings {
+		if !clipping.AddedAt.IsZero() && (startReadAt.IsZero() || clipping.AddedAt.Before(startReadAt)) {
+			startReadAt = clipping.AddedAt
+		}
+	}
+	if !startReadAt.IsZero() {
+		startReadAt = time.Date(startReadAt.Year(), startReadAt.Month(), startReadAt.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	review = Review{
+		ReadingStatus: entity.ReadingStatusReading,
+		StartReadAt:   startReadAt,
+		UserID:        userID,
+		BookID:        book.ID,
+	}
+	if err := tx.Create(&review).Error; err != nil {
+		return Review{}, err
+	}
+
+	return review, nil
+}
+
+// ハイライトを引用として登録する(同じ位置、同じ本文の引用が登録済みの場合は登録しない)
+func importKindleHighlight(tx *gorm.DB, review Review, clipping kindleClipping, dryRun bool) (*Quote, bool, error) {
+	var quote Quote
+	if review.ID != 0 {
+		err := tx.Where("review_id = ? AND location = ? AND text = ?", review.ID, clipping.Location, clipping.Text).First(&quote).Error
+		if err == nil {
+			return &quote, false, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+	}
+
+	quote = Quote{
+		Text:          clipping.Text,
+		Page:          clipping.Page,
+		Location:      clipping.Location,
+		HighlightedAt: clipping.AddedAt,
+		ReviewID:      review.ID,
+	}
+	if dryRun {
+		return &quote, true, nil
+	}
+	if err := tx.Create(&quote).Error; err != nil {
+		return nil, false, err
+	}
+
+	return &quote, true, nil
+}
+
+// メモを、同じ位置のハイライトの引用に紐づける
+// 対応するハイライトが無い場合は、メモ自体を引用として登録する
+func importKindleNote(tx *gorm.DB, review Review, clipping kindleClipping, quotesByLocationEnd map[string]*Quote, dryRun bool) (bool, error) {
+	quote, ok := quotesByLocationEnd[clipping.Location]
+	if !ok && review.ID != 0 {
+		var found Quote
+		err := tx.Where("review_id = ? AND (location = ? OR location LIKE ?)", review.ID, clipping.Location, "%-"+clipping.Location).First(&found).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, err
+		}
+		if err == nil {
+			quote, ok = &found, true
+		}
+	}
+
+	if !ok {
+		clipping.Kind = kindleClippingHighlight
+		_, created, err := importKindleHighlight(tx, review, clipping, dryRun)
+		return created, err
+	}
+
+	if quote.Note == clipping.Text {
+		return false, nil
+	}
+	quote.Note = clipping.Text
+	if dryRun || quote.ID == 0 {
+		return true, nil
+	}
+	return true, tx.Model(quote).Update("note", quote.Note).Error
+}
+
+// 位置("123-125"形式)の終了位置を返す
+func kindleLocationEnd(location string) string {
+	if i := strings.LastIndex(location, "-"); i >= 0 {
+		end := location[i+1:]
+		start := location[:i]
+		// "1234-56"のように終了位置が省略されている場合は補完する
+		if len(end) < len(start) {
+			end = start[:len(start)-len(end)] + end
+		}
+		return end
+	}
+	return location
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// 副題の区切りとみなす文字列(正規化後の文字列で照合する)
+var subtitleSeparators = []string{":", " - ", "—", "―", "~", "(", "[", "【", "《", "〈"}
+
+// 書籍の照合用に文字列を正規化する
+// Unicode NFKC正規化、全角半角の統一、小文字化を行い、空白、句読点、記号を取り除く
+// 例: "ノルウェイの森 (上)" → "ノルウェイの森上"、"Ｈａｒｒｙ　Ｐｏｔｔｅｒ!" → "harrypotter"
+func normalizeText(s string) string {
+	s = width.Fold.String(norm.NFKC.String(s))
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// 副題、巻数等の括弧書きを除いたタイトルを正規化して返す
+// 例: "1Q84 BOOK1 (新潮文庫)" → "1q84book1"、"Sapiens: A Brief History of Humankind" → "sapiens"
+func normalizeMainTitle(title string) string {
+	folded := width.Fold.String(norm.NFKC.String(title))
+	cut := len(folded)
+	for _, separator := range subtitleSeparators {
+		// 区切りより前にタイトルが無い場合は、区切りとみなさない
+		if i := strings.Index(folded, separator); i > 0 && i < cut && normalizeText(folded[:i]) != "" {
+			cut = i
+		}
+	}
+	return normalizeText(folded[:cut])
+}
+
+// 著者名の照合用キーを返す
+// 姓名の順序の違い(例: "Murakami, Haruki"と"Haruki Murakami")を無視するため、語を並べ替えて連結する
+// 日本語等の著者名は姓名の順序が一定のため、空白の有無(例: "村上 春樹"と"村上春樹")のみ無視する
+func authorNameKey(name string) string {
+	name = width.Fold.String(norm.NFKC.String(name))
+	for _, r := range name {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return normalizeText(name)
+		}
+	}
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, "")
+}
+
+// 巻数を表す文字(数字に加えて判定する)
+const volumeMarkers = "上中下前後編巻第"
+
+// 正規化したタイトルが、巻数のみ異なるか判定する
+// 例: "ノルウェイの森上"と"ノルウェイの森下"、"ワンピース1"と"ワンピース2"
+func isDifferentVolume(a, b string) bool {
+	runesA, runesB := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < len(runesA) && prefix < len(runesB) && runesA[prefix] == runesB[prefix] {
+		prefix++
+	}
+	// 数字の途中で区切らないよう、共通部分から数字を除く(例: "1"と"12")
+	for prefix > 0 && unicode.IsDigit(runesA[prefix-1]) {
+		prefix--
+	}
+	suffix := 0
+	for suffix < len(runesA)-prefix && suffix < len(runesB)-prefix && runesA[len(runesA)-1-suffix] == runesB[len(runesB)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && unicode.IsDigit(runesA[len(runesA)-suffix]) {
+		suffix--
+	}
+	diffA, diffB := runesA[prefix:len(runesA)-suffix], runesB[prefix:len(runesB)-suffix]
+	if len(diffA) == 0 || len(diffB) == 0 {
+		return false
+	}
+	isVolume := func(runes []rune) bool {
+		for _, r := range runes {
+			if !unicode.IsDigit(r) && !strings.ContainsRune(volumeMarkers, r) {
+				return false
+			}
+		}
+		return true
+	}
+	return isVolume(diffA) && isVolume(diffB)
+}
+
+// 2つの文字列の類似度を、文字のバイグラムのダイス係数(0〜1)で返す
+func textSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	bigramsA, bigramsB := bigrams(a), bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	matches := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			matches++
+		}
+	}
+	return 2 * float64(matches) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// 文字列の文字のバイグラムを返す(1文字の場合は、その文字のみを返す)
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) == 1 {
+		return []string{s}
+	}
+	var result []string
+	for i := 0; i+1 < len(runes); i++ {
+		result = append(result, string(runes[i:i+2]))
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Notification entity.Notification
+
+// 通知の取得に用いるカラム(価格の通知の場合は、通知時点以前の最新の購入リンクを含む)
+const responseNotificationColumns = `notifications.id, notifications.type, notifications.message, notifications.price,
+	notifications.is_read, notifications.created_at, notifications.book_id, books.title as book_title,
+	coalesce((SELECT price_histories.buy_link FROM price_histories WHERE price_histories.book_id = notifications.book_id
+		AND price_histories.checked_at <= notifications.created_at ORDER BY price_histories.checked_at desc, price_histories.id desc LIMIT 1), '') as buy_link`
+
+// 通知一覧取得サービス
+// ?unread=trueの場合は、未読の通知のみを返す
+func (s Service) GetNotifications(c *gin.Context) ([]entity.ResponseNotification, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	notifications := []entity.ResponseNotification{}
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.ResponseNotification{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.ResponseNotification{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.ResponseNotification{}, http.StatusNotFound, err
+	}
+
+	// SELECT ... FROM notifications join books on notifications.book_id = books.id
+	// WHERE notifications.user_id = ? [AND notifications.is_read = false] ORDER BY notifications.created_at desc, notifications.id desc;
+	query := db.Model(&Notification{}).Select(responseNotificationColumns).
+		Joins("join books on notifications.book_id = books.id").
+		Where("notifications.user_id = ?", user.ID)
+	if unread, _ := strconv.ParseBool(c.Query("unread")); unread {
+		query = query.Where("notifications.is_read = ?", false)
+	}
+	if err := query.Order("notifications.created_at desc, notifications.id desc").Scan(&notifications).Error; err != nil {
+		return []entity.ResponseNotification{}, http.StatusInternalServerError, err
+	}
+	return notifications, http.StatusOK, nil
+}
+
+// 通知の既読化サービス
+func (s Service) ReadNotification(c *gin.Context) (entity.ResponseNotification, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var notification Notification
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ResponseNotification{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ResponseNotification{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ResponseNotification{}, http.StatusNotFound, err
+	}
+
+	// ログインユーザの通知を取得
+	if err := db.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&notification).Error; err != nil {
+		return entity.ResponseNotification{}, http.StatusNotFound, err
+	}
+
+	if err := db.Model(&notification).Update("is_read", true).Error; err != nil {
+		return entity.ResponseNotification{}, http.StatusInternalServerError, err
+	}
+
+	var response entity.ResponseNotification
+	if err := db.Model(&Notification{}).Select(responseNotificationColumns).
+		Joins("join books on notifications.book_id = books.id").
+		Where("notifications.id = ?", notification.ID).Scan(&response).Error; err != nil {
+		return entity.ResponseNotification{}, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/KoyoMiyazaki/Book-Reviewer/provider"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceWatch entity.PriceWatch
+type PriceHistory entity.PriceHistory
+type WatchPriceRequest entity.WatchPriceRequest
+
+// 環境変数PRICE_CHECK_INTERVALが未設定の場合の、価格の取得間隔
+const defaultPriceCheckInterval = 6 * time.Hour
+
+// レスポンスに含める価格履歴の最大件数
+const maxPriceHistory = 100
+
+// 価格の取得の同時実行を防ぐためのロック
+var priceCheckMutex sync.Mutex
+
+// 書籍の価格取得サービス
+// ログインしている場合は、ログインユーザの価格の監視設定を含めて返す
+func (s Service) GetBookPrices(c *gin.Context) (entity.BookPricesResponse, StatusCode, error) {
+	db := db.GetDB()
+	var book Book
+
+	// Authorizationヘッダが指定されていない場合は監視設定を返却しないため、それの判定を行う
+	var user User
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader != "" {
+		// JWTトークン検証
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		token, statusCode, err := s.VerifyToken(tokenString)
+		if err != nil {
+			return entity.BookPricesResponse{}, statusCode, err
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+
+		if !ok || !token.Valid {
+			return entity.BookPricesResponse{}, http.StatusForbidden, err
+		}
+
+		// メールアドレスをキーに、ユーザを取得
+		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+			return entity.BookPricesResponse{}, http.StatusNotFound, err
+		}
+	}
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return entity.BookPricesResponse{}, http.StatusNotFound, err
+	}
+
+	// 価格履歴を新しい順に取得
+	// SELECT * FROM price_histories WHERE book_id = ? ORDER BY checked_at desc, id desc LIMIT 100;
+	var histories []PriceHistory
+	if err := db.Where("book_id = ?", book.ID).Order("checked_at desc, id desc").Limit(maxPriceHistory).Find(&histories).Error; err != nil {
+		return entity.BookPricesResponse{}, http.StatusInternalServerError, err
+	}
+
+	response := entity.BookPricesResponse{
+		BookID:  book.ID,
+		History: []entity.ResponsePrice{},
+	}
+	for _, history := range histories {
+		response.History = append(response.History, entity.ResponsePrice{
+			IsForSale: history.IsForSale,
+			Price:     history.Price,
+			BuyLink:   history.BuyLink,
+			CheckedAt: history.CheckedAt,
+		})
+	}
+	if len(response.History) > 0 {
+		current := response.History[0]
+		response.Current = &current
+	}
+
+	if user.ID != 0 {
+		var watch PriceWatch
+		err := db.Where("user_id = ? AND book_id = ?", user.ID, book.ID).First(&watch).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.BookPricesResponse{}, http.StatusInternalServerError, err
+		}
+		if err == nil {
+			responseWatch := toResponsePriceWatch(watch)
+			response.Watch = &responseWatch
+		}
+	}
+	return response, http.StatusOK, nil
+}
+
+// 書籍の価格の監視登録サービス
+// 既に監視している場合は目標価格を更新し、登録後に最初の価格の取得を非同期で行う
+func (s Service) WatchBookPrice(c *gin.Context) (entity.ResponsePriceWatch, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var book Book
+	var request WatchPriceRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.ResponsePriceWatch{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.ResponsePriceWatch{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.ResponsePriceWatch{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.ResponsePriceWatch{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.ResponsePriceWatch{}, http.StatusNotFound, err
+	}
+
+	// IDをキーに、書籍を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&book).Error; err != nil {
+		return entity.ResponsePriceWatch{}, http.StatusNotFound, err
+	}
+
+	// 書籍メタデータプロバイダから価格を取得できない書籍は監視できない
+	if _, ok := priceLookupKey(book); !ok {
+		return entity.ResponsePriceWatch{}, http.StatusBadRequest, errors.New("price of this book cannot be tracked")
+	}
+
+	// 監視を登録、または目標価格を更新(目標価格が変わるため、通知済みの価格はリセットする)
+	var watch PriceWatch
+	err = db.Where("user_id = ? AND book_id = ?", user.ID, book.ID).First(&watch).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ResponsePriceWatch{}, http.StatusInternalServerError, err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		watch = PriceWatch{
+			TargetPrice: request.TargetPrice,
+			UserID:      user.ID,
+			BookID:      book.ID,
+		}
+		if err := db.Create(&watch).Error; err != nil {
+			return entity.ResponsePriceWatch{}, http.StatusInternalServerError, err
+		}
+	} else {
+		watch.TargetPrice = request.TargetPrice
+		watch.LastNotifiedPrice = 0
+		watch.Notified = false
+		if err := db.Model(&watch).Select("target_price", "last_notified_price", "notified", "updated_at").Updates(&watch).Error; err != nil {
+			return entity.ResponsePriceWatch{}, http.StatusInternalServerError, err
+		}
+	}
+
+	// 最初の価格を取得し、既に目標価格以下の場合は通知する
+	go func() {
+		if err := checkBookPrice(context.Background(), book); err != nil {
+			log.Printf("failed to check price of book %d: %v", book.ID, err)
+		}
+	}()
+
+	return toResponsePriceWatch(watch), http.StatusOK, nil
+}
+
+// 書籍の価格の監視解除サービス
+func (s Service) UnwatchBookPrice(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var watch PriceWatch
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// ログインユーザの監視設定を取得
+	if err := db.Where("user_id = ? AND book_id = ?", user.ID, c.Param("id")).First(&watch).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	if err := db.Delete(&watch).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// 書籍の価格を定期的に取得するジョブを開始する
+// 環境変数PRICE_CHECK_INTERVALで間隔を指定する("0"の場合は実行しない)
+func StartPriceTracking() {
+	interval := defaultPriceCheckInterval
+	if value := os.Getenv("PRICE_CHECK_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("invalid PRICE_CHECK_INTERVAL %q: %v", value, err)
+		} else {
+			interval = parsed
+		}
+	}
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			if priceCheckMutex.TryLock() {
+				if err := checkWatchedBookPrices(); err != nil {
+					log.Printf("failed to check book prices: %v", err)
+				}
+				priceCheckMutex.Unlock()
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// 監視されている全書籍の価格を取得する
+// 個々の書籍の取得に失敗した場合は、ログに出力して次の書籍へ進む
+func checkWatchedBookPrices() error {
+	db := db.GetDB()
+
+	// SELECT * FROM books WHERE id IN (SELECT DISTINCT book_id FROM price_watches) ORDER BY id;
+	var books []Book
+	if err := db.Where("id IN (?)", db.Model(&PriceWatch{}).Distinct("book_id")).Order("id").Find(&books).Error; err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		if err := checkBookPrice(context.Background(), book); err != nil {
+			log.Printf("failed to check price of book %d: %v", book.ID, err)
+		}
+	}
+	return nil
+}
+
+// 書籍メタデータプロバイダから書籍の価格を取得して履歴に記録し、目標価格以下となった監視ユーザに通知する
+// 価格履歴は、前回の取得時から価格、または販売状況が変わった場合のみ記録する
+func checkBookPrice(ctx context.Context, book Book) error {
+	lookup, ok := priceLookupKey(book)
+	if !ok {
+		return nil
+	}
+	// 最新の価格を取得するため、キャッシュは用いない
+	providerBook, err := lookup(provider.WithoutCache(ctx), provider.Default())
+	if err != nil {
+		return err
+	}
+	if !providerBook.IsForSale {
+		providerBook.Price = 0
+		providerBook.BuyLink = ""
+	}
+
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 監視登録時の取得と定期的な取得が同時に実行された場合に、価格履歴の記録と通知が重複しないよう、書籍をロックする
+		// SELECT id FROM books WHERE id = [書籍ID] FOR UPDATE;
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", book.ID).First(&Book{}).Error; err != nil {
+			return err
+		}
+
+		var latest PriceHistory
+		err := tx.Where("book_id = ?", book.ID).Order("checked_at desc, id desc").First(&latest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) || latest.IsForSale != providerBook.IsForSale || latest.Price != providerBook.Price {
+			history := PriceHistory{
+				IsForSale: providerBook.IsForSale,
+				Price:     providerBook.Price,
+				BuyLink:   providerBook.BuyLink,
+				Provider:  providerBook.Provider,
+				CheckedAt: time.Now().Unix(),
+				BookID:    book.ID,
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+
+		return notifyPriceWatchers(tx, book, providerBook.IsForSale, providerBook.Price)
+	})
+}
+
+// 価格が目標価格以下となった監視ユーザに通知する
+// 同じ価格で繰り返し通知しないよう、通知済みの価格より下がった場合のみ再度通知する
+func notifyPriceWatchers(tx *gorm.DB, book Book, isForSale bool, price uint) error {
+	var watches []PriceWatch
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("book_id = ?", book.ID).Find(&watches).Error; err != nil {
+		return err
+	}
+
+	for _, watch := range watches {
+		switch priceWatchActionOf(watch, isForSale, price) {
+		case priceWatchNotify:
+			notification := Notification{
+				Type:    entity.NotificationTypePriceDrop,
+				Message: fmt.Sprintf("「%s」の価格が%dになりました(目標価格: %d)", book.Title, price, watch.TargetPrice),
+				Price:   price,
+				UserID:  watch.UserID,
+				BookID:  book.ID,
+			}
+			if err := tx.Create(&notification).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&watch).Updates(map[string]interface{}{"last_notified_price": price, "notified": true}).Error; err != nil {
+				return err
+			}
+		case priceWatchReset:
+			// 目標価格を上回った場合は、再び下がった際に通知するためリセットする
+			if err := tx.Model(&watch).Updates(map[string]interface{}{"last_notified_price": 0, "notified": false}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 取得した価格に対する、価格の監視の処理
+const (
+	priceWatchNone   = iota // 何もしない
+	priceWatchNotify        // 通知する
+	priceWatchReset         // 通知済みの状態をリセットする
+)
+
+// 取得した価格に対して、価格の監視で行う処理を返す
+// 価格が0(無料)の場合も、通知済みであれば繰り返し通知しない
+func priceWatchActionOf(watch PriceWatch, isForSale bool, price uint) int {
+	switch {
+	case isForSale && price <= watch.TargetPrice && (!watch.Notified || price < watch.LastNotifiedPrice):
+		return priceWatchNotify
+	case (!isForSale || price > watch.TargetPrice) && watch.Notified:
+		return priceWatchReset
+	}
+	return priceWatchNone
+}
+
+// 書籍メタデータプロバイダから書籍を取得する関数を返す
+// Google Booksから登録した書籍はプロバイダのIDで、それ以外の書籍はISBNで取得する(いずれも無い場合はfalseを返す)
+func priceLookupKey(book Book) (func(context.Context, provider.BookProvider) (entity.ProviderBook, error), bool) {
+	switch {
+	case book.Provider == provider.GoogleBooksName && book.VolumeID != "":
+		return func(ctx context.Context, p provider.BookProvider) (entity.ProviderBook, error) {
+			return p.LookupByID(ctx, book.VolumeID)
+		}, true
+	case book.ISBN13 != "" || book.ISBN10 != "":
+		isbn := book.ISBN13
+		if isbn == "" {
+			isbn = book.ISBN10
+		}
+		return func(ctx context.Context, p provider.BookProvider) (entity.ProviderBook, error) {
+			return p.LookupByISBN(ctx, isbn)
+		}, true
+	}
+	return nil, false
+}
+
+// 価格の監視設定をレスポンス用構造体に変換する
+func toResponsePriceWatch(watch PriceWatch) entity.ResponsePriceWatch {
+	return entity.ResponsePriceWatch{
+		BookID:      watch.BookID,
+		TargetPrice: watch.TargetPrice,
+		CreatedAt:   watch.CreatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import "testing"
+
+// 取得した価格を順に適用し、通知した価格の一覧を返す(notifyPriceWatchersと同じく監視の状態を更新する)
+func simulatePriceWatch(watch PriceWatch, prices []struct {
+	isForSale bool
+	price     uint
+}) []uint {
+	notified := []uint{}
+	for _, p := range prices {
+		switch priceWatchActionOf(watch, p.isForSale, p.price) {
+		case priceWatchNotify:
+			notified = append(notified, p.price)
+			watch.LastNotifiedPrice, watch.Notified = p.price, true
+		case priceWatchReset:
+			watch.LastNotifiedPrice, watch.Notified = 0, false
+		}
+	}
+	return notified
+}
+
+func TestPriceWatchNotifications(t *testing.T) {
+	type check = struct {
+		isForSale bool
+		price     uint
+	}
+	tests := []struct {
+		name   string
+		target uint
+		prices []check
+		want   []uint
+	}{
+		{"目標価格以下になった場合に1回だけ通知する", 1000, []check{{true, 1200}, {true, 900}, {true, 900}, {true, 900}}, []uint{900}},
+		{"通知済みの価格より下がった場合は再度通知する", 1000, []check{{true, 900}, {true, 800}, {true, 850}}, []uint{900, 800}},
+		{"無料になった場合も1回だけ通知する", 1000, []check{{true, 1200}, {true, 0}, {true, 0}, {true, 0}}, []uint{0}},
+		{"通知済みの価格から無料になった場合は通知する", 1000, []check{{true, 900}, {true, 0}, {true, 0}}, []uint{900, 0}},
+		{"目標価格を上回った後に再び下がった場合は通知する", 1000, []check{{true, 0}, {true, 1200}, {true, 0}}, []uint{0, 0}},
+		{"販売されなくなった後に再び販売された場合は通知する", 1000, []check{{true, 900}, {false, 0}, {true, 900}}, []uint{900, 900}},
+		{"販売されていない場合は通知しない", 1000, []check{{false, 0}, {false, 0}}, []uint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := simulatePriceWatch(PriceWatch{TargetPrice: tt.target}, tt.prices)
+			if len(got) != len(tt.want) {
+				t.Fatalf("notified %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("notified %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type Quote entity.Quote
+type CreateQuoteRequest entity.CreateQuoteRequest
+type UpdateQuoteRequest entity.UpdateQuoteRequest
+type GetQuotesResponse entity.GetQuotesResponse
+type ResponseQuote entity.ResponseQuote
+
+// レスポンス用引用構造体の取得に用いるカラム
+const responseQuoteColumns = "quotes.id, quotes.text, quotes.page, quotes.location, quotes.note, quotes.tags, to_char(quotes.highlighted_at, 'YYYY-MM-DD') as highlighted_at, quotes.review_id, books.title as book_title, books.author as book_author"
+
+// レビューに紐づく引用取得サービス
+func (s Service) GetQuotes(c *gin.Context) ([]entity.ResponseQuote, StatusCode, error) {
+	db := db.GetDB()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.ResponseQuote{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.ResponseQuote{}, http.StatusForbidden, err
+	}
+
+	// IDをキーに、レビューを取得
+	var review Review
+	if err := db.Where("id = ?", c.Param("id")).First(&review).Error; err != nil {
+		return []entity.ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	var user User
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// 対象レビューのユーザIDと、ログインユーザIDが一致していなければ取得しない
+	if review.UserID != user.ID {
+		return []entity.ResponseQuote{}, http.StatusForbidden, fmt.Errorf("couldn't get quotes of this review")
+	}
+
+	quotes, err := getQuotesByReviewIDs([]uint{review.ID})
+	if err != nil {
+		return []entity.ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	return quotes, http.StatusOK, nil
+}
+
+// 引用登録サービス
+func (s Service) CreateQuote(c *gin.Context) (ResponseQuote, StatusCode, error) {
+	db := db.GetDB()
+	var request CreateQuoteRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return ResponseQuote{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return ResponseQuote{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return ResponseQuote{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return ResponseQuote{}, http.StatusBadRequest, err
+	}
+
+	// IDをキーに、レビューを取得
+	var review Review
+	if err := db.Where("id = ?", c.Param("id")).First(&review).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	var user User
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// 対象レビューのユーザIDと、ログインユーザIDが一致していなければ登録しない
+	if review.UserID != user.ID {
+		return ResponseQuote{}, http.StatusForbidden, fmt.Errorf("couldn't add a quote to this review")
+	}
+
+	// Quoteを新規作成
+	newQuote := Quote{
+		Text:     request.Text,
+		Page:     request.Page,
+		Location: request.Location,
+		Note:     request.Note,
+		Tags:     request.Tags,
+		ReviewID: review.ID,
+	}
+
+	if err := db.Create(&newQuote).Error; err != nil {
+		return ResponseQuote{}, http.StatusBadRequest, err
+	}
+
+	// IDをキーに、書籍を取得
+	var book Book
+	if err := db.Where("id = ?", review.BookID).First(&book).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// レスポンス用データ生成
+	responseQuote := ResponseQuote{
+		ID:         newQuote.ID,
+		Text:       newQuote.Text,
+		Page:       newQuote.Page,
+		Location:   newQuote.Location,
+		Note:       newQuote.Note,
+		Tags:       newQuote.Tags,
+		ReviewID:   newQuote.ReviewID,
+		BookTitle:  book.Title,
+		BookAuthor: book.Author,
+	}
+
+	return responseQuote, http.StatusCreated, nil
+}
+
+// 引用更新サービス
+func (s Service) UpdateQuote(c *gin.Context) (ResponseQuote, StatusCode, error) {
+	db := db.GetDB()
+	var request UpdateQuoteRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return ResponseQuote{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return ResponseQuote{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return ResponseQuote{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return ResponseQuote{}, http.StatusBadRequest, err
+	}
+
+	// レビューID、引用IDをキーに、引用を取得
+	var quote Quote
+	if err := db.Where("id = ? AND review_id = ?", c.Param("quoteId"), c.Param("id")).First(&quote).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// IDをキーに、レビューを取得
+	var review Review
+	if err := db.Where("id = ?", quote.ReviewID).First(&review).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	var user User
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// 更新対象引用のレビューのユーザIDと、ログインユーザIDが一致していなければ更新しない
+	if review.UserID != user.ID {
+		return ResponseQuote{}, http.StatusForbidden, fmt.Errorf("couldn't update this quote")
+	}
+
+	// 引用を更新
+	quote.Text = request.Text
+	quote.Page = request.Page
+	quote.Location = request.Location
+	quote.Note = request.Note
+	quote.Tags = request.Tags
+	if err := db.Save(&quote).Error; err != nil {
+		return ResponseQuote{}, http.StatusInternalServerError, err
+	}
+
+	// IDをキーに、書籍を取得
+	var book Book
+	if err := db.Where("id = ?", review.BookID).First(&book).Error; err != nil {
+		return ResponseQuote{}, http.StatusNotFound, err
+	}
+
+	// レスポンス用データ生成
+	responseQuote := ResponseQuote{
+		ID:            quote.ID,
+		Text:          quote.Text,
+		Page:          quote.Page,
+		Location:      quote.Location,
+		Note:          quote.Note,
+		Tags:          quote.Tags,
+		HighlightedAt: formatDate(quote.HighlightedAt),
+		ReviewID:      quote.ReviewID,
+		BookTitle:     book.Title,
+		BookAuthor:    book.Author,
+	}
+
+	return responseQuote, http.StatusOK, nil
+}
+
+// 引用削除サービス
+func (s Service) DeleteQuote(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// レビューID、引用IDをキーに、引用を取得
+	var quote Quote
+	if err := db.Where("id = ? AND review_id = ?", c.Param("quoteId"), c.Param("id")).First(&quote).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// IDをキーに、レビューを取得
+	var review Review
+	if err := db.Where("id = ?", quote.ReviewID).First(&review).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	var user User
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// 削除対象引用のレビューのユーザIDと、ログインユーザIDが一致していなければ削除しない
+	if review.UserID != user.ID {
+		return http.StatusForbidden, fmt.Errorf("couldn't delete this quote")
+	}
+
+	if err := db.Delete(&quote).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// 引用検索サービス
+func (s Service) SearchQuotes(c *gin.Context) (GetQuotesResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var results []entity.ResponseQuote
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return GetQuotesResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return GetQuotesResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return GetQuotesResponse{}, http.StatusNotFound, err
+	}
+
+	// ページパラメータ取得、指定されてない場合は1を設定
+	var page int
+	if c.Query("page") == "" {
+		page = 1
+	} else {
+		page, err = strconv.Atoi(c.Query("page"))
+		if err != nil {
+			return GetQuotesResponse{}, http.StatusBadRequest, err
+		}
+	}
+
+	// 検索ワード、タグ名で絞り込み(未指定の場合はユーザの全引用が対象)
+	query := db.Model(&Quote{}).Joins("join reviews on quotes.review_id = reviews.id").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID)
+	if search := c.Query("search"); search != "" {
+		pattern := fmt.Sprintf("%%%s%%", search)
+		query = query.Where("quotes.text ILIKE ? OR quotes.note ILIKE ? OR books.title ILIKE ?", pattern, pattern, pattern)
+	}
+	if tag := c.Query("tag"); tag != "" {
+		query = query.Where("quotes.tags ILIKE ?", fmt.Sprintf("%%%s%%", tag))
+	}
+
+	// ユーザIDをキーに、引用を取得
+	if err := query.Session(&gorm.Session{}).Select(responseQuoteColumns).Order("quotes.updated_at desc").Limit(10).Offset(10 * (page - 1)).Scan(&results).Error; err != nil {
+		// SELECT quotes.id, quotes.text, quotes.page, quotes.location, quotes.note, quotes.tags,
+		//   to_char(quotes.highlighted_at, 'YYYY-MM-DD') as highlighted_at, quotes.review_id,
+		//   books.title as book_title, books.author as book_author
+		// FROM `quotes` join `reviews` on quotes.review_id = reviews.id join `books` on reviews.book_id = books.id
+		// WHERE reviews.user_id = user.ID
+		//   [AND (quotes.text ILIKE %[search]% OR quotes.note ILIKE %[search]% OR books.title ILIKE %[search]%)]
+		//   [AND quotes.tags ILIKE %[tag]%]
+		// ORDER BY quotes.updated_at DESC
+		// LIMIT 10 OFFSET [10 * (page-1)]
+		return GetQuotesResponse{}, http.StatusNotFound, err
+	}
+	// ハイライト日時が0001-01-01の場合は空文字を格納する
+	for i := range results {
+		results[i].HighlightedAt = timeStrCoalesce(results[i].HighlightedAt, "")
+	}
+
+	// 引用の総件数を取得
+	var totalRows int64
+	if err := query.Count(&totalRows).Error; err != nil {
+		return GetQuotesResponse{}, http.StatusNotFound, err
+	}
+
+	// レスポンス用データ生成
+	getQuotesResponse := GetQuotesResponse{
+		ResponseQuotes: results,
+		TotalPages:     totalRows/10 + 1,
+	}
+
+	return getQuotesResponse, http.StatusOK, nil
+}
+
+// レビューIDをキーに、引用を取得する
+func getQuotesByReviewIDs(reviewIDs []uint) ([]entity.ResponseQuote, error) {
+	db := db.GetDB()
+	results := []entity.ResponseQuote{}
+	if len(reviewIDs) == 0 {
+		return results, nil
+	}
+
+	if err := db.Model(&Quote{}).Select(responseQuoteColumns).Joins("join reviews on quotes.review_id = reviews.id").Joins("join books on reviews.book_id = books.id").Where("quotes.review_id IN ?", reviewIDs).Order("quotes.page, quotes.id").Scan(&results).Error; err != nil {
+		// SELECT quotes.id, quotes.text, quotes.page, quotes.location, quotes.note, quotes.tags,
+		//   to_char(quotes.highlighted_at, 'YYYY-MM-DD') as highlighted_at, quotes.review_id,
+		//   books.title as book_title, books.author as book_author
+		// FROM `quotes` join `reviews` on quotes.review_id = reviews.id join `books` on reviews.book_id = books.id
+		// WHERE quotes.review_id IN [reviewIDs]
+		// ORDER BY quotes.page, quotes.id
+		return []entity.ResponseQuote{}, err
+	}
+	// ハイライト日時が0001-01-01の場合は空文字を格納する
+	for i := range results {
+		results[i].HighlightedAt = timeStrCoalesce(results[i].HighlightedAt, "")
+	}
+
+	return results, nil
+}
+
+// includeQuotesパラメータが指定されている場合、各レビューに引用を付与する
+func attachQuotes(c *gin.Context, reviews []entity.ResponseReview) error {
+	if include, _ := strconv.ParseBool(c.Query("includeQuotes")); !include {
+		return nil
+	}
+
+	reviewIDs := make([]uint, 0, len(reviews))
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID)
+	}
+
+	quotes, err := getQuotesByReviewIDs(reviewIDs)
+	if err != nil {
+		return err
+	}
+
+	quotesByReviewID := map[uint][]entity.ResponseQuote{}
+	for _, quote := range quotes {
+		quotesByReviewID[quote.ReviewID] = append(quotesByReviewID[quote.ReviewID], quote)
+	}
+	for i := range reviews {
+		reviews[i].Quotes = quotesByReviewID[reviews[i].ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// 読書ペースの算出に用いる期間の設定
+const (
+	defaultPaceDays = 30  // 期間が指定されていない場合の日数(今日まで)
+	maxPaceDays     = 365 // 指定できる期間の最大の日数
+)
+
+// 読書中の書籍の、読書ペースの算出に用いる項目
+type currentlyReadingReview struct {
+	ID                uint
+	BookID            uint
+	BookTitle         string
+	BookAuthor        string
+	BookThumbnailLink string
+	ReadPages         uint
+	NumOfPages        uint
+	StartReadAt       time.Time
+	CreatedAt         int64
+}
+
+// 読書中の書籍一覧取得サービス
+// ?days=[日数]で読書ペースの算出に用いる期間を指定する(未指定の場合は30日)
+// 読書ペースは、期間内に記録した読んだページ数から求める(日付はユーザのタイムゾーンで集計する)
+func (s Service) GetCurrentlyReading(c *gin.Context) (entity.CurrentlyReadingResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.CurrentlyReadingResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.CurrentlyReadingResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.CurrentlyReadingResponse{}, http.StatusNotFound, err
+	}
+
+	// パラメータの検証
+	paceDays := defaultPaceDays
+	if c.Query("days") != "" {
+		paceDays, err = strconv.Atoi(c.Query("days"))
+		if err != nil || paceDays < 1 || paceDays > maxPaceDays {
+			return entity.CurrentlyReadingResponse{}, http.StatusBadRequest, fmt.Errorf("days must be between 1 and %d", maxPaceDays)
+		}
+	}
+	location := userLocation(user)
+	today := dateOf(time.Now().In(location))
+	paceStart := today.AddDate(0, 0, -(paceDays - 1))
+	paceStartUnix := time.Date(paceStart.Year(), paceStart.Month(), paceStart.Day(), 0, 0, 0, 0, location).Unix()
+
+	// 読書中のレビューを取得
+	var reviews []currentlyReadingReview
+	if err := db.Model(&Review{}).Select("reviews.id, reviews.book_id, books.title as book_title, books.author as book_author, books.thumbnail_link as book_thumbnail_link, reviews.read_pages, books.num_of_pages, reviews.start_read_at, reviews.created_at").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.reading_status = ?", user.ID, entity.ReadingStatusReading).Scan(&reviews).Error; err != nil {
+		// SELECT reviews.id, reviews.book_id, books.title AS book_title, books.author AS book_author, books.thumbnail_link AS book_thumbnail_link,
+		//   reviews.read_pages, books.num_of_pages, reviews.start_read_at, reviews.created_at
+		// FROM reviews JOIN books ON reviews.book_id = books.id
+		// WHERE reviews.user_id = [user.ID] AND reviews.reading_status = 'Reading';
+		return entity.CurrentlyReadingResponse{}, http.StatusInternalServerError, err
+	}
+	reviewIDs := []uint{}
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID)
+	}
+
+	// 期間内の読んだページ数の記録と、読書中の書籍の全ての記録を取得
+	var activities []ReadingActivity
+	if err := db.Select("pages", "created_at", "review_id").Where("user_id = ? AND type = ? AND (created_at >= ? OR review_id IN ?)", user.ID, entity.ReadingActivityPagesLogged, paceStartUnix, append(reviewIDs, 0)).Find(&activities).Error; err != nil {
+		// SELECT pages, created_at, review_id FROM reading_activities
+		// WHERE user_id = [user.ID] AND type = 'pagesLogged' AND (created_at >= [期間の開始日時] OR review_id IN [reviewIDs]);
+		return entity.CurrentlyReadingResponse{}, http.StatusInternalServerError, err
+	}
+	var firstLoggedAt int64
+	if err := db.Model(&ReadingActivity{}).Select("coalesce(min(created_at), 0)").Where("user_id = ? AND type = ?", user.ID, entity.ReadingActivityPagesLogged).Scan(&firstLoggedAt).Error; err != nil {
+		// SELECT coalesce(min(created_at), 0) FROM reading_activities WHERE user_id = [user.ID] AND type = 'pagesLogged';
+		return entity.CurrentlyReadingResponse{}, http.StatusInternalServerError, err
+	}
+
+	return buildCurrentlyReading(reviews, activities, firstLoggedAt, location, paceStart, today), http.StatusOK, nil
+}
+
+// 読書中の書籍ごとに、進捗、読書ペースと読了見込み日を求め、読了に近い順に並べる
+// 書籍の読書ペースは、期間の開始日と読書開始日の遅い方から今日までに記録した、読んだページ数から求める
+// その書籍の記録が無い場合は、全ての書籍での読書ペースで読み続けるものとする
+func buildCurrentlyReading(reviews []currentlyReadingReview, activities []ReadingActivity, firstLoggedAt int64, location *time.Location, paceStart, today time.Time) entity.CurrentlyReadingResponse {
+	dayOf := func(unix int64) time.Time {
+		return dateOf(time.Unix(unix, 0).In(location))
+	}
+	// 期間の開始日と、指定した日の遅い方から今日までの日数
+	paceDaysSince := func(start time.Time) int {
+		if start.Before(paceStart) {
+			start = paceStart
+		}
+		if start.After(today) {
+			start = today
+		}
+		return daysBetween(start, today) + 1
+	}
+
+	var totalPages int64
+	pagesOfReview := map[uint]int64{}
+	lastReadAt := map[uint]time.Time{}
+	for _, activity := range activities {
+		date := dayOf(activity.CreatedAt)
+		if date.After(lastReadAt[activity.ReviewID]) {
+			lastReadAt[activity.ReviewID] = date
+		}
+		if date.Before(paceStart) {
+			continue
+		}
+		totalPages += int64(activity.Pages)
+		pagesOfReview[activity.ReviewID] += int64(activity.Pages)
+	}
+
+	response := entity.CurrentlyReadingResponse{
+		PaceDays: daysBetween(paceStart, today) + 1,
+		Books:    []entity.CurrentlyReadingBook{},
+	}
+	if totalPages > 0 {
+		response.PagesPerDay = roundProgress(float64(totalPages) / float64(paceDaysSince(dayOf(firstLoggedAt))))
+	}
+
+	for _, review := range reviews {
+		book := entity.CurrentlyReadingBook{
+			ReviewID:          review.ID,
+			BookID:            review.BookID,
+			BookTitle:         review.BookTitle,
+			BookAuthor:        review.BookAuthor,
+			BookThumbnailLink: review.BookThumbnailLink,
+			ReadPages:         review.ReadPages,
+			NumOfPages:        review.NumOfPages,
+			EstimatedDaysLeft: -1,
+			StartReadAt:       formatDate(review.StartReadAt),
+			LastReadAt:        formatDate(lastReadAt[review.ID]),
+		}
+
+		// 読書ペース(読書開始日が未設定の場合は、レビューの登録日から読み始めたものとする)
+		startReadAt := review.StartReadAt
+		if startReadAt.IsZero() {
+			startReadAt = dayOf(review.CreatedAt)
+		}
+		if pages := pagesOfReview[review.ID]; pages > 0 {
+			book.PagesPerDay = roundProgress(float64(pages) / float64(paceDaysSince(startReadAt)))
+			book.PaceSource = entity.ReadingPaceSourceBook
+		} else if response.PagesPerDay > 0 {
+			book.PagesPerDay = response.PagesPerDay
+			book.PaceSource = entity.ReadingPaceSourceOverall
+		}
+
+		// 進捗と読了見込み日(ページ数が不明な場合は求めない)
+		if review.NumOfPages > 0 {
+			if review.ReadPages >= review.NumOfPages {
+				book.Percentage = 100
+				book.EstimatedDaysLeft = 0
+			} else {
+				book.RemainingPages = review.NumOfPages - review.ReadPages
+				book.Percentage = roundProgress(float64(review.ReadPages) / float64(review.NumOfPages) * 100)
+				if book.PagesPerDay > 0 {
+					book.EstimatedDaysLeft = int(math.Ceil(float64(book.RemainingPages) / book.PagesPerDay))
+				}
+			}
+			if book.EstimatedDaysLeft >= 0 {
+				book.EstimatedFinishDate = formatDate(today.AddDate(0, 0, book.EstimatedDaysLeft))
+			}
+		}
+		response.Books = append(response.Books, book)
+	}
+
+	// 読了に近い順(進捗の割合の高い順、同じ場合は残りのページ数の少ない順)に並べる
+	// ページ数が不明な書籍は最後にする
+	sort.SliceStable(response.Books, func(i, j int) bool {
+		a, b := response.Books[i], response.Books[j]
+		if (a.NumOfPages > 0) != (b.NumOfPages > 0) {
+			return a.NumOfPages > 0
+		}
+		if a.Percentage != b.Percentage {
+			return a.Percentage > b.Percentage
+		}
+		if a.RemainingPages != b.RemainingPages {
+			return a.RemainingPages < b.RemainingPages
+		}
+		return a.ReviewID < b.ReviewID
+	})
+	return response
+}
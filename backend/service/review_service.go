@@ -23,6 +23,9 @@ type GetReviewsResponse entity.GetReviewsResponse
 type ResponseReview entity.ResponseReview
 type GetReviewStatsResponse entity.GetReviewStatsResponse
 
+// レスポンス用レビュー構造体の取得に用いるカラム
+const responseReviewColumns = "reviews.id, reviews.comment, reviews.rating, reviews.reading_status, reviews.read_pages, to_char(reviews.start_read_at, 'YYYY-MM-DD') as start_read_at, to_char(reviews.finish_read_at, 'YYYY-MM-DD') as finish_read_at, reviews.tags, reviews.visibility, books.title as book_title, books.author as book_author, books.thumbnail_link as book_thumbnail_link, books.published_date as book_published_date, books.num_of_pages as book_num_of_pages"
+
 // レビュー取得サービス
 func (s Service) GetReviews(c *gin.Context) (GetReviewsResponse, StatusCode, error) {
 	db := db.GetDB()
@@ -60,18 +63,20 @@ func (s Service) GetReviews(c *gin.Context) (GetReviewsResponse, StatusCode, err
 		}
 	}
 
-	// ユーザIDをキーに、レビューを取得
-	if err := db.Model(&Review{}).Select("reviews.id, reviews.comment, reviews.rating, reviews.reading_status, reviews.read_pages, to_char(reviews.start_read_at, 'YYYY-MM-DD') as start_read_at, to_char(reviews.finish_read_at, 'YYYY-MM-DD') as finish_read_at, reviews.tags, books.title as book_title, books.author as book_author, books.thumbnail_link as book_thumbnail_link, books.published_date as book_published_date, books.num_of_pages as book_num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID).Order("reviews.updated_at desc").Limit(10).Offset(10 * (page - 1)).Scan(&results).Error; err != nil {
+	// ユーザIDをキーに、レビューを取得(ジャンルが指定されている場合は絞り込む)
+	query := filterReviewsByGenre(c, db.Model(&Review{}).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID))
+	if err := query.Session(&gorm.Session{}).Select(responseReviewColumns).Order("reviews.updated_at desc").Limit(10).Offset(10 * (page - 1)).Scan(&results).Error; err != nil {
 		// SELECT reviews.id, reviews.comment, reviews.rating, reviews.reading_status, reviews.read_pages,
 		//   to_char(reviews.start_read_at, 'YYYY-MM-DD') as start_read_at,
 		//   to_char(reviews.finish_read_at, 'YYYY-MM-DD') as finish_read_at,
-		//   reviews.tags,
+		//   reviews.tags, reviews.visibility,
 		//   books.title as book_title, books.author as book_author,
 		//   books.thumbnail_link as book_thumbnail_link,
 		//   books.published_date as book_published_date,
 		//   books.num_of_pages as book_num_of_pages
 		// FROM `reviews` join `books` on reviews.book_id = books.id
 		// WHERE reviews.user_id = user.ID
+		//   [AND reviews.book_id IN (SELECT book_id FROM book_genres join genres ... WHERE genres.slug = [genre])]
 		// ORDER BY reviews.updated_at DESC
 		// LIMIT 10 OFFSET [10 * (page-1)]
 		return GetReviewsResponse{}, http.StatusNotFound, err
@@ -82,12 +87,18 @@ func (s Service) GetReviews(c *gin.Context) (GetReviewsResponse, StatusCode, err
 		results[i].FinishReadAt = timeStrCoalesce(results[i].FinishReadAt, "")
 	}
 
+	// includeQuotesパラメータが指定されている場合は引用を付与
+	if err := attachQuotes(c, results); err != nil {
+		return GetReviewsResponse{}, http.StatusNotFound, err
+	}
+
 	// レビューの総件数を取得
 	var totalRows int64
-	if err := db.Model(&Review{}).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID).Count(&totalRows).Error; err != nil {
+	if err := query.Count(&totalRows).Error; err != nil {
 		// SELECT count(1)
 		// FROM `reviews` join `books` on reviews.book_id = books.id
 		// WHERE reviews.user_id = user.ID
+		//   [AND reviews.book_id IN (SELECT book_id FROM book_genres join genres ... WHERE genres.slug = [genre])]
 		return GetReviewsResponse{}, http.StatusNotFound, err
 	}
 
@@ -137,9 +148,26 @@ func (s Service) CreateReview(c *gin.Context) (ResponseReview, StatusCode, error
 		return ResponseReview{}, http.StatusNotFound, err
 	}
 
+	// ISBNが指定されている場合は検証し、ISBN-10とISBN-13の組に正規化
+	identity := bookIdentity{
+		Title:    request.BookTitle,
+		Author:   request.BookAuthor,
+		Provider: request.BookProvider,
+		VolumeID: request.BookVolumeID,
+	}
+	for _, isbn := range []string{request.BookISBN13, request.BookISBN10} {
+		if isbn == "" || identity.ISBN13 != "" {
+			continue
+		}
+		identity.ISBN10, identity.ISBN13, err = normalizeISBN(isbn)
+		if err != nil {
+			return ResponseReview{}, http.StatusBadRequest, err
+		}
+	}
+
 	// Bookがデータベースに無い場合は新規登録
-	var book Book
-	if err := db.Where("title = ? AND author = ?", request.BookTitle, request.BookAuthor).First(&book).Error; err != nil {
+	book, err := findBook(db, identity)
+	if err != nil {
 		// Bookを新規作成
 		book = Book{
 			Title:         request.BookTitle,
@@ -147,33 +175,41 @@ func (s Service) CreateReview(c *gin.Context) (ResponseReview, StatusCode, error
 			ThumbnailLink: request.BookThumbnailLink,
 			PublishedDate: request.BookPublishedDate,
 			NumOfPages:    request.BookNumOfPages,
+			ISBN10:        identity.ISBN10,
+			ISBN13:        identity.ISBN13,
+			Provider:      identity.Provider,
+			VolumeID:      identity.VolumeID,
+			Categories:    strings.Join(request.BookCategories, ","),
+			Description:   request.BookDescription,
+			Publisher:     request.BookPublisher,
+			Language:      request.BookLanguage,
 		}
 
-		if err := db.Create(&book).Error; err != nil {
+		// 著者、作品の関連付けも合わせて行う(同時に同じ書籍が登録された場合は、その書籍を用いる)
+		if _, err := createOrFindBook(db, &book, request.BookContributors, request.BookWorkID, user.ID); err != nil {
 			return ResponseReview{}, http.StatusBadRequest, err
 		}
+	} else if err := fillBookIdentity(db, &book, identity); err != nil {
+		// 登録済みのBookに無い識別子を補完
+		return ResponseReview{}, http.StatusInternalServerError, err
 	}
 
 	// 文字列→日付オブジェクトへ変換
-	var convertedStartReadAt, convertedFinishReadAt time.Time
-	if request.StartReadAt != "" {
-		dateLayout := "2006-01-02"
-		convertedStartReadAt, err = time.Parse(dateLayout, request.StartReadAt)
-		if err != nil {
-			return ResponseReview{}, http.StatusBadRequest, err
-		}
-	} else {
-		convertedStartReadAt = time.Time{}
+	convertedStartReadAt, err := parseDate(request.StartReadAt)
+	if err != nil {
+		return ResponseReview{}, http.StatusBadRequest, err
+	}
+	convertedFinishReadAt, err := parseDate(request.FinishReadAt)
+	if err != nil {
+		return ResponseReview{}, http.StatusBadRequest, err
 	}
 
-	if request.FinishReadAt != "" {
-		dateLayout := "2006-01-02"
-		convertedFinishReadAt, err = time.Parse(dateLayout, request.FinishReadAt)
-		if err != nil {
-			return ResponseReview{}, http.StatusBadRequest, err
-		}
-	} else {
-		convertedFinishReadAt = time.Time{}
+	// 読了日が指定されずに読了となった場合は、読書目標の進捗に含めるため今日(ユーザのタイムゾーンでの日付)を読了日とする
+	convertedFinishReadAt = defaultFinishReadAt(request.ReadingStatus, convertedFinishReadAt, userToday(user))
+
+	// 公開範囲が指定されていない場合は非公開とする
+	if request.Visibility == "" {
+		request.Visibility = entity.ReviewVisibilityPrivate
 	}
 
 	// Reviewを新規作成
@@ -185,12 +221,28 @@ func (s Service) CreateReview(c *gin.Context) (ResponseReview, StatusCode, error
 		StartReadAt:   convertedStartReadAt,
 		FinishReadAt:  convertedFinishReadAt,
 		Tags:          request.Tags,
+		Visibility:    request.Visibility,
 		UserID:        user.ID,
 		BookID:        book.ID,
 	}
 
-	if err := db.Create(&newReview).Error; err != nil {
-		return ResponseReview{}, http.StatusBadRequest, err
+	// レビューの登録、読書記録と統計の更新を、まとめて行う(いずれかに失敗した場合はレビューも登録しない)
+	statusCode = http.StatusInternalServerError
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newReview).Error; err != nil {
+			statusCode = http.StatusBadRequest
+			return err
+		}
+
+		// 読んだページ数を読書記録に登録(レビューの登録自体は、レビューの登録日時から集計する)
+		if err := recordReadingActivities(tx, Review{ReadingStatus: newReview.ReadingStatus}, newReview); err != nil {
+			return err
+		}
+		// 読了月の統計を更新
+		return refreshMonthlyReviewStats(tx, user.ID, newReview.FinishReadAt)
+	})
+	if err != nil {
+		return ResponseReview{}, statusCode, err
 	}
 
 	// レスポンス用データ生成
@@ -200,8 +252,9 @@ func (s Service) CreateReview(c *gin.Context) (ResponseReview, StatusCode, error
 		ReadingStatus:     newReview.ReadingStatus,
 		ReadPages:         newReview.ReadPages,
 		StartReadAt:       request.StartReadAt,
-		FinishReadAt:      request.FinishReadAt,
+		FinishReadAt:      formatDate(convertedFinishReadAt),
 		Tags:              newReview.Tags,
+		Visibility:        newReview.Visibility,
 		BookTitle:         book.Title,
 		BookAuthor:        book.Author,
 		BookThumbnailLink: book.ThumbnailLink,
@@ -243,47 +296,33 @@ func (s Service) UpdateReview(c *gin.Context) (ResponseReview, StatusCode, error
 		return ResponseReview{}, http.StatusBadRequest, err
 	}
 
-	// IDをキーに、レビューを取得
-	id := c.Param("id")
-	var review Review
-	if err := db.Where("id = ?", id).First(&review).Error; err != nil {
-		return ResponseReview{}, http.StatusNotFound, err
-	}
-
 	// メールアドレスをキーに、ユーザを取得
 	var user User
 	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
 		return ResponseReview{}, http.StatusNotFound, err
 	}
 
-	// 更新対象レビューのユーザIDと、ログインユーザIDが一致していなければ更新しない
-	if review.UserID != user.ID {
-		return ResponseReview{}, http.StatusForbidden, fmt.Errorf("couldn't update this review")
+	// IDをキーに、ログインユーザの更新対象レビューを取得
+	review, statusCode, err := findOwnReview(db, c.Param("id"), user.ID, "update")
+	if err != nil {
+		return ResponseReview{}, statusCode, err
 	}
 
 	// 文字列→日付オブジェクトへ変換
-	var convertedStartReadAt, convertedFinishReadAt time.Time
-	if request.StartReadAt != "" {
-		dateLayout := "2006-01-02"
-		convertedStartReadAt, err = time.Parse(dateLayout, request.StartReadAt)
-		if err != nil {
-			return ResponseReview{}, http.StatusBadRequest, err
-		}
-	} else {
-		convertedStartReadAt = time.Time{}
+	convertedStartReadAt, err := parseDate(request.StartReadAt)
+	if err != nil {
+		return ResponseReview{}, http.StatusBadRequest, err
 	}
-
-	if request.FinishReadAt != "" {
-		dateLayout := "2006-01-02"
-		convertedFinishReadAt, err = time.Parse(dateLayout, request.FinishReadAt)
-		if err != nil {
-			return ResponseReview{}, http.StatusBadRequest, err
-		}
-	} else {
-		convertedFinishReadAt = time.Time{}
+	convertedFinishReadAt, err := parseDate(request.FinishReadAt)
+	if err != nil {
+		return ResponseReview{}, http.StatusBadRequest, err
 	}
 
+	// 読了日が指定されずに読了となった場合は、読書目標の進捗に含めるため今日(ユーザのタイムゾーンでの日付)を読了日とする
+	convertedFinishReadAt = defaultFinishReadAt(request.ReadingStatus, convertedFinishReadAt, userToday(user))
+
 	// レビューを更新
+	before := review
 	review.Comment = request.Comment
 	review.Rating = request.Rating
 	review.ReadingStatus = request.ReadingStatus
@@ -291,7 +330,23 @@ func (s Service) UpdateReview(c *gin.Context) (ResponseReview, StatusCode, error
 	review.StartReadAt = convertedStartReadAt
 	review.FinishReadAt = convertedFinishReadAt
 	review.Tags = request.Tags
-	if err := db.Save(&review).Error; err != nil {
+	if request.Visibility != "" {
+		review.Visibility = request.Visibility
+	}
+	// レビューの更新、読書記録と統計の更新を、まとめて行う(いずれかに失敗した場合はレビューも更新しない)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&review).Error; err != nil {
+			return err
+		}
+
+		// 読書ステータス、読んだページ数の変化を読書記録に登録
+		if err := recordReadingActivities(tx, before, review); err != nil {
+			return err
+		}
+		// 変更前後の読了月の統計を更新
+		return refreshMonthlyReviewStats(tx, user.ID, before.FinishReadAt, review.FinishReadAt)
+	})
+	if err != nil {
 		return ResponseReview{}, http.StatusInternalServerError, err
 	}
 
@@ -308,8 +363,9 @@ func (s Service) UpdateReview(c *gin.Context) (ResponseReview, StatusCode, error
 		ReadingStatus:     review.ReadingStatus,
 		ReadPages:         review.ReadPages,
 		StartReadAt:       request.StartReadAt,
-		FinishReadAt:      request.FinishReadAt,
+		FinishReadAt:      formatDate(convertedFinishReadAt),
 		Tags:              review.Tags,
+		Visibility:        review.Visibility,
 		BookTitle:         book.Title,
 		BookAuthor:        book.Author,
 		BookThumbnailLink: book.ThumbnailLink,
@@ -339,25 +395,27 @@ func (s Service) DeleteReview(c *gin.Context) (StatusCode, error) {
 		return http.StatusForbidden, err
 	}
 
-	// IDをキーに、レビューを取得
-	id := c.Param("id")
-	var review Review
-	if err := db.Where("id = ?", id).First(&review).Error; err != nil {
-		return http.StatusNotFound, err
-	}
-
 	// メールアドレスをキーに、ユーザを取得
 	var user User
 	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
 		return http.StatusNotFound, err
 	}
 
-	// 削除対象レビューのユーザIDと、ログインユーザIDが一致していなければ削除しない
-	if review.UserID != user.ID {
-		return http.StatusForbidden, fmt.Errorf("couldn't delete this review")
+	// IDをキーに、ログインユーザの削除対象レビューを取得
+	review, statusCode, err := findOwnReview(db, c.Param("id"), user.ID, "delete")
+	if err != nil {
+		return statusCode, err
 	}
 
-	if err := db.Delete(&review).Error; err != nil {
+	// レビューの削除と統計の更新を、まとめて行う
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		// 読了月の統計を更新
+		return refreshMonthlyReviewStats(tx, user.ID, review.FinishReadAt)
+	})
+	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
@@ -389,77 +447,62 @@ func (s Service) GetReviewStats(c *gin.Context) (GetReviewStatsResponse, StatusC
 		return GetReviewStatsResponse{}, http.StatusNotFound, err
 	}
 
-	// パラメータ取得、指定されてない場合は今月、今年を設定
-	var month, year int
-	if c.Query("month") == "" {
-		month = int(time.Now().Month())
-	} else {
+	// パラメータ取得、指定されてない場合はユーザのタイムゾーンでの今月、今年を設定
+	today := userToday(user)
+	month, year := int(today.Month()), today.Year()
+	if c.Query("month") != "" {
 		month, err = strconv.Atoi(c.Query("month"))
 		if err != nil {
 			return GetReviewStatsResponse{}, http.StatusBadRequest, err
 		}
+		if month < 1 || month > 12 {
+			return GetReviewStatsResponse{}, http.StatusBadRequest, fmt.Errorf("month must be between 1 and 12")
+		}
 	}
-	if c.Query("year") == "" {
-		year = int(time.Now().Year())
-	} else {
+	if c.Query("year") != "" {
 		year, err = strconv.Atoi(c.Query("year"))
 		if err != nil {
 			return GetReviewStatsResponse{}, http.StatusBadRequest, err
 		}
+		if year < 1 || year > 9999 {
+			return GetReviewStatsResponse{}, http.StatusBadRequest, fmt.Errorf("year must be between 1 and 9999")
+		}
 	}
 
-	startDateOfMonth, endDateOfMonth := getStartAndEndDateOfMonth(year, month)
-
-	var subQuery *gorm.DB
-	formattedYear := fmt.Sprintf("%04d", year)
-	// 対象月の読んだ書籍数を取得
-	var numOfReadBooksOfMonth int64
-	if err := db.Model(&Review{}).Select("reviews.user_id").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? and reviews.finish_read_at between ? and ?", user.ID, startDateOfMonth, endDateOfMonth).Count(&numOfReadBooksOfMonth).Error; err != nil {
-		// SELECT reviews.user_id FROM reviews JOIN books ON reviews.book_id = books.id
-		// WHERE reviews.user_id = [user.ID] AND reviews.finish_read_at BETWEEN ['YYYY-MM-01'] AND ['YYYY-MM-[28|29|30|31]']
+	// 対象月、対象年の読んだ書籍数、ページ数を、月別の統計から取得
+	var statOfMonth, statOfYear MonthlyReviewStat
+	if err := db.Where("user_id = ? AND year = ? AND month = ?", user.ID, year, month).Limit(1).Find(&statOfMonth).Error; err != nil {
+		// SELECT * FROM monthly_review_stats WHERE user_id = [user.ID] AND year = [year] AND month = [month] LIMIT 1;
 		return GetReviewStatsResponse{}, http.StatusNotFound, err
 	}
-
-	// 対象年の読んだ書籍数を取得
-	var numOfReadBooksOfYear int64
-	if err := db.Model(&Review{}).Select("reviews.user_id").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? and reviews.finish_read_at between ? and ?", user.ID, fmt.Sprintf("%s-01-01", formattedYear), fmt.Sprintf("%s-12-31", formattedYear)).Count(&numOfReadBooksOfYear).Error; err != nil {
-		// SELECT reviews.user_id FROM reviews JOIN books ON reviews.book_id = books.id
-		// WHERE reviews.user_id = [user.ID] AND reviews.finish_read_at BETWEEN ['YYYY-01-01'] AND ['YYYY-12-31']
+	if err := db.Model(&MonthlyReviewStat{}).Select("coalesce(sum(num_of_read_books), 0) as num_of_read_books, coalesce(sum(num_of_read_pages), 0) as num_of_read_pages").Where("user_id = ? AND year = ?", user.ID, year).Scan(&statOfYear).Error; err != nil {
+		// SELECT coalesce(sum(num_of_read_books), 0) AS num_of_read_books, coalesce(sum(num_of_read_pages), 0) AS num_of_read_pages
+		// FROM monthly_review_stats WHERE user_id = [user.ID] AND year = [year];
 		return GetReviewStatsResponse{}, http.StatusNotFound, err
 	}
 
-	// 対象月の読んだページ数を取得
-	var numOfReadPagesOfMonth int64
-	subQuery = db.Model(&Review{}).Select("reviews.user_id, books.num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.finish_read_at between ? and ?", startDateOfMonth, endDateOfMonth)
-	if err := db.Table("(?) as x", subQuery).Select("sum(x.num_of_pages) as num_of_read_pages").Group("x.user_id").Having("x.user_id = ?", user.ID).Find(&numOfReadPagesOfMonth).Error; err != nil {
-		// SELECT sum(x.num_of_pages) AS num_Of_read_pages
-		// FROM (
-		// 	SELECT reviews.user_id, books.num_of_pages FROM reviews JOIN books ON reviews.book_id = books.id
-		// 	WHERE finish_read_at BETWEEN ['YYYY-MM-01'] AND ['YYYY-MM-[28|29|30|31]']
-		// ) AS x
-		// GROUP BY x.user_id HAVING x.user_id = [user.ID];
-		return GetReviewStatsResponse{}, http.StatusNotFound, err
-	}
+	// 対象年の期間(読了日が期間の最終日の0時以降でも含まれるよう、半開区間とする)
+	startOfYear, endOfYear := yearRange(year)
 
-	// 対象年の読んだページ数を取得
-	var numOfReadPagesOfYear int64
-	subQuery = db.Model(&Review{}).Select("reviews.user_id, books.num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.finish_read_at between ? and ?", fmt.Sprintf("%s-01-01", formattedYear), fmt.Sprintf("%s-12-31", formattedYear))
-	if err := db.Table("(?) as x", subQuery).Select("sum(x.num_of_pages) as num_of_read_pages").Group("x.user_id").Having("x.user_id = ?", user.ID).Find(&numOfReadPagesOfYear).Error; err != nil {
-		// SELECT sum(x.num_of_pages) AS num_Of_read_pages
-		// FROM (
-		// 	SELECT reviews.user_id, books.num_of_pages FROM reviews JOIN books ON reviews.book_id = books.id
-		// 	WHERE finish_read_at BETWEEN ['YYYY-01-01'] AND ['YYYY-12-31']
-		// ) AS x
-		// GROUP BY x.user_id HAVING x.user_id = [user.ID];
+	// 対象年の読んだ書籍数を、ジャンル別に取得
+	genresOfYear := []entity.GenreCount{}
+	if err := db.Model(&Review{}).Select("genres.slug, genres.name, count(distinct reviews.id) as count").Joins("join book_genres on book_genres.book_id = reviews.book_id").Joins("join genres on genres.id = book_genres.genre_id").Where("reviews.user_id = ? and reviews.finish_read_at >= ? and reviews.finish_read_at < ?", user.ID, startOfYear, endOfYear).Group("genres.id").Order("count desc, genres.id").Scan(&genresOfYear).Error; err != nil {
+		// SELECT genres.slug, genres.name, count(distinct reviews.id) as count
+		// FROM reviews JOIN book_genres ON book_genres.book_id = reviews.book_id
+		// JOIN genres ON genres.id = book_genres.genre_id
+		// WHERE reviews.user_id = [user.ID] AND reviews.finish_read_at >= ['YYYY-01-01'] AND reviews.finish_read_at < [翌年の1月1日]
+		// GROUP BY genres.id
+		// ORDER BY count DESC, genres.id
 		return GetReviewStatsResponse{}, http.StatusNotFound, err
 	}
 
 	// レスポンス用データ生成
 	getReviewStatsResponse := GetReviewStatsResponse{
-		NumOfReadBooksOfMonth: numOfReadBooksOfMonth,
-		NumOfReadPagesOfMonth: numOfReadPagesOfMonth,
-		NumOfReadBooksOfYear:  numOfReadBooksOfYear,
-		NumOfReadPagesOfYear:  numOfReadPagesOfYear,
+		NumOfReadBooksOfMonth: statOfMonth.NumOfReadBooks,
+		NumOfReadPagesOfMonth: statOfMonth.NumOfReadPages,
+		NumOfReadBooksOfYear:  statOfYear.NumOfReadBooks,
+		NumOfReadPagesOfYear:  statOfYear.NumOfReadPages,
+		GenresOfYear:          genresOfYear,
 	}
 
 	return getReviewStatsResponse, http.StatusOK, nil
@@ -505,11 +548,11 @@ func (s Service) FilterReviewByTag(c *gin.Context) (GetReviewsResponse, StatusCo
 	tagName := c.Param("tagName")
 
 	// ユーザID、タグ名をキーに、レビューを取得
-	if err := db.Model(&Review{}).Select("reviews.id, reviews.comment, reviews.rating, reviews.reading_status, reviews.read_pages, to_char(reviews.start_read_at, 'YYYY-MM-DD') as start_read_at, to_char(reviews.finish_read_at, 'YYYY-MM-DD') as finish_read_at, reviews.tags, books.title as book_title, books.author as book_author, books.thumbnail_link as book_thumbnail_link, books.published_date as book_published_date, books.num_of_pages as book_num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.tags ILIKE ?", user.ID, fmt.Sprintf("%%%s%%", tagName)).Order("reviews.updated_at desc").Limit(10).Offset(10 * (page - 1)).Scan(&results).Error; err != nil {
+	if err := db.Model(&Review{}).Select(responseReviewColumns).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.tags ILIKE ?", user.ID, fmt.Sprintf("%%%s%%", tagName)).Order("reviews.updated_at desc").Limit(10).Offset(10 * (page - 1)).Scan(&results).Error; err != nil {
 		// SELECT reviews.id, reviews.comment, reviews.rating, reviews.reading_status, reviews.read_pages,
 		//   to_char(reviews.start_read_at, 'YYYY-MM-DD') as start_read_at,
 		//   to_char(reviews.finish_read_at, 'YYYY-MM-DD') as finish_read_at,
-		//   reviews.tags,
+		//   reviews.tags, reviews.visibility,
 		//   books.title as book_title, books.author as book_author,
 		//   books.thumbnail_link as book_thumbnail_link,
 		//   books.published_date as book_published_date,
@@ -526,6 +569,11 @@ func (s Service) FilterReviewByTag(c *gin.Context) (GetReviewsResponse, StatusCo
 		results[i].FinishReadAt = timeStrCoalesce(results[i].FinishReadAt, "")
 	}
 
+	// includeQuotesパラメータが指定されている場合は引用を付与
+	if err := attachQuotes(c, results); err != nil {
+		return GetReviewsResponse{}, http.StatusNotFound, err
+	}
+
 	// レビューの総件数を取得
 	var totalRows int64
 	if err := db.Model(&Review{}).Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.tags ILIKE ?", user.ID, fmt.Sprintf("%%%s%%", tagName)).Count(&totalRows).Error; err != nil {
@@ -544,6 +592,30 @@ func (s Service) FilterReviewByTag(c *gin.Context) (GetReviewsResponse, StatusCo
 	return getReviewsResponse, http.StatusOK, nil
 }
 
+// IDをキーにレビューを取得し、ログインユーザのレビューであるか検証する
+// operationはエラーメッセージに用いる操作名("update"、"delete"など)
+func findOwnReview(tx *gorm.DB, id any, userID uint, operation string) (Review, StatusCode, error) {
+	var review Review
+	if err := tx.Where("id = ?", id).First(&review).Error; err != nil {
+		return Review{}, http.StatusNotFound, err
+	}
+
+	// 対象レビューのユーザIDと、ログインユーザIDが一致していなければ操作させない
+	if review.UserID != userID {
+		return Review{}, http.StatusForbidden, fmt.Errorf("couldn't %s this review", operation)
+	}
+
+	return review, http.StatusOK, nil
+}
+
+// genreパラメータが指定されている場合は、そのジャンルの書籍のレビューに絞り込む
+func filterReviewsByGenre(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if genre := c.Query("genre"); genre != "" {
+		query = query.Where("reviews.book_id IN (SELECT book_genres.book_id FROM book_genres join genres on genres.id = book_genres.genre_id WHERE genres.slug = ?)", genre)
+	}
+	return query
+}
+
 // 日付文字列が0001-01-01の場合はデフォルト値を、そうでない場合は元の値を返す
 func timeStrCoalesce(timeStrArg, defaultTimeStr string) string {
 	if timeStrArg != "0001-01-01" {
@@ -553,27 +625,10 @@ func timeStrCoalesce(timeStrArg, defaultTimeStr string) string {
 	}
 }
 
-// 対象月の最初と最後の日付を、YYYY-MM-DDの形式でそれぞれ返却する
-func getStartAndEndDateOfMonth(year, month int) (string, string) {
-	// 対象月の最終日を判定
-	var lastDayOfMonth string
-	switch month {
-	case 2:
-		if year%4 == 0 && year%100 != 0 || year%400 == 0 {
-			lastDayOfMonth = "29"
-		} else {
-			lastDayOfMonth = "28"
-		}
-	case 4, 6, 9, 11:
-		lastDayOfMonth = "30"
-	case 1, 3, 5, 7, 8, 10, 12:
-		lastDayOfMonth = "31"
+// 読了日が未設定で読書ステータスが読了の場合は、今日を読了日として返す
+func defaultFinishReadAt(readingStatus string, finishReadAt, today time.Time) time.Time {
+	if readingStatus == entity.ReadingStatusFinish && finishReadAt.IsZero() {
+		return today
 	}
-	formattedYear := fmt.Sprintf("%04d", year)
-	formattedMonth := fmt.Sprintf("%02d", month)
-
-	startDateOfMonth := fmt.Sprintf("%s-%s-01", formattedYear, formattedMonth)
-	endDateOfMonth := fmt.Sprintf("%s-%s-%s", formattedYear, formattedMonth, lastDayOfMonth)
-
-	return startDateOfMonth, endDateOfMonth
+	return finishReadAt
 }
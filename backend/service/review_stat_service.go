@@ -0,0 +1,101 @@
+package service
+
+import (
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MonthlyReviewStat entity.MonthlyReviewStat
+
+// 読了日を含む月の統計を、その月のレビューから集計し直す
+// レビューの登録、更新、削除時に、変更前後の読了日を指定して呼び出す(読了日が未設定の場合は何もしない)
+func refreshMonthlyReviewStats(tx *gorm.DB, userID uint, finishReadAts ...time.Time) error {
+	months := map[time.Time]bool{}
+	for _, finishReadAt := range finishReadAts {
+		if finishReadAt.IsZero() {
+			continue
+		}
+		start, _ := monthRange(finishReadAt.Year(), finishReadAt.Month())
+		months[start] = true
+	}
+
+	for start := range months {
+		_, end := monthRange(start.Year(), start.Month())
+		stat := MonthlyReviewStat{UserID: userID, Year: start.Year(), Month: int(start.Month())}
+		if err := tx.Model(&Review{}).Select("count(*) as num_of_read_books, coalesce(sum(books.num_of_pages), 0) as num_of_read_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? and reviews.finish_read_at >= ? and reviews.finish_read_at < ?", userID, start, end).Scan(&stat).Error; err != nil {
+			// SELECT count(*) AS num_of_read_books, coalesce(sum(books.num_of_pages), 0) AS num_of_read_pages
+			// FROM reviews JOIN books ON reviews.book_id = books.id
+			// WHERE reviews.user_id = [userID] AND reviews.finish_read_at >= [月の1日] AND reviews.finish_read_at < [翌月の1日]
+			return err
+		}
+
+		// 読了したレビューが無くなった月は削除する
+		if stat.NumOfReadBooks == 0 {
+			if err := tx.Where("user_id = ? AND year = ? AND month = ?", stat.UserID, stat.Year, stat.Month).Delete(&MonthlyReviewStat{}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}, {Name: "month"}},
+			DoUpdates: clause.AssignmentColumns([]string{"num_of_read_books", "num_of_read_pages", "updated_at"}),
+		}).Create(&stat).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 書籍のページ数の変更、書籍の統合時に、その書籍を読了した全てのユーザの統計を集計し直す
+func refreshBookReviewStats(tx *gorm.DB, bookID uint) error {
+	var reviews []Review
+	if err := tx.Select("user_id", "finish_read_at").Where("book_id = ? AND finish_read_at > '0001-01-01'", bookID).Find(&reviews).Error; err != nil {
+		// SELECT user_id, finish_read_at FROM reviews WHERE book_id = [bookID] AND finish_read_at > '0001-01-01';
+		return err
+	}
+
+	finishReadAts := map[uint][]time.Time{}
+	for _, review := range reviews {
+		finishReadAts[review.UserID] = append(finishReadAts[review.UserID], review.FinishReadAt)
+	}
+	for userID, dates := range finishReadAts {
+		if err := refreshMonthlyReviewStats(tx, userID, dates...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 全てのユーザの月別の統計を、レビューから集計し直す
+// 差分での更新と、レビューの内容に食い違いが生じた場合に用いる(go run . rebuild-stats)
+func RebuildMonthlyReviewStats() error {
+	db := db.GetDB()
+	now := time.Now().Unix()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&MonthlyReviewStat{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO monthly_review_stats (user_id, year, month, num_of_read_books, num_of_read_pages, created_at, updated_at)
+SELECT reviews.user_id, extract(year from reviews.finish_read_at)::int, extract(month from reviews.finish_read_at)::int, count(*), coalesce(sum(books.num_of_pages), 0), ?, ?
+FROM reviews JOIN books ON reviews.book_id = books.id
+WHERE reviews.finish_read_at > '0001-01-01'
+GROUP BY 1, 2, 3`, now, now).Error
+	})
+}
+
+// 月別の統計が未作成の場合(統計の導入直後)に、レビューから作成する
+func MigrateMonthlyReviewStats() error {
+	db := db.GetDB()
+	var count int64
+	if err := db.Model(&MonthlyReviewStat{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return RebuildMonthlyReviewStats()
+}
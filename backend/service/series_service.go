@@ -0,0 +1,334 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+type Series entity.Series
+type SeriesEntry entity.SeriesEntry
+type CreateSeriesRequest entity.CreateSeriesRequest
+type AddSeriesEntryRequest entity.AddSeriesEntryRequest
+
+// シリーズの巻の取得に用いるカラム(ログインユーザの読書ステータスを含む)
+const responseSeriesEntryColumns = `series_entries.id, series_entries.position, series_entries.work_id, works.title, works.author,
+	(SELECT books.id FROM books WHERE books.work_id = works.id ORDER BY books.id LIMIT 1) as book_id,
+	(SELECT books.thumbnail_link FROM books WHERE books.work_id = works.id ORDER BY books.id LIMIT 1) as thumbnail_link,
+	coalesce((SELECT reviews.reading_status FROM reviews join books on reviews.book_id = books.id WHERE books.work_id = works.id AND reviews.user_id = ? ORDER BY reviews.updated_at desc LIMIT 1), '') as reading_status`
+
+// シリーズ詳細取得サービス
+// ログインしている場合は、巻ごとにログインユーザの読書ステータスを返す
+func (s Service) GetSeries(c *gin.Context) (entity.SeriesResponse, StatusCode, error) {
+	db := db.GetDB()
+	var series Series
+
+	// Authorizationヘッダが指定されていない場合は読書ステータスを返却しないため、それの判定を行う
+	var user User
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader != "" {
+		// JWTトークン検証
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		token, statusCode, err := s.VerifyToken(tokenString)
+		if err != nil {
+			return entity.SeriesResponse{}, statusCode, err
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+
+		if !ok || !token.Valid {
+			return entity.SeriesResponse{}, http.StatusForbidden, err
+		}
+
+		// メールアドレスをキーに、ユーザを取得
+		if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+			return entity.SeriesResponse{}, http.StatusNotFound, err
+		}
+	}
+
+	// IDをキーに、シリーズを取得
+	if err := db.Where("id = ?", c.Param("id")).First(&series).Error; err != nil {
+		return entity.SeriesResponse{}, http.StatusNotFound, err
+	}
+
+	// シリーズの巻を取得(未ログインの場合、ユーザIDは0となるため読書ステータスは空文字となる)
+	entries, err := getSeriesEntries(db, series.ID, user.ID)
+	if err != nil {
+		return entity.SeriesResponse{}, http.StatusInternalServerError, err
+	}
+
+	response := entity.SeriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		Entries:     entries,
+	}
+	return response, http.StatusOK, nil
+}
+
+// シリーズ登録サービス
+func (s Service) CreateSeries(c *gin.Context) (entity.SeriesResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request CreateSeriesRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.SeriesResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.SeriesResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.SeriesResponse{}, http.StatusNotFound, err
+	}
+
+	// Seriesを新規作成
+	newSeries := Series{
+		Name:        request.Name,
+		Description: request.Description,
+		UserID:      user.ID,
+	}
+	if err := db.Create(&newSeries).Error; err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// レスポンス用データ生成
+	response := entity.SeriesResponse{
+		ID:          newSeries.ID,
+		Name:        newSeries.Name,
+		Description: newSeries.Description,
+		Entries:     []entity.ResponseSeriesEntry{},
+	}
+	return response, http.StatusOK, nil
+}
+
+// シリーズの巻登録サービス
+// 書籍の作品を巻として登録する(登録済みの場合は巻数を更新する)
+func (s Service) AddSeriesEntry(c *gin.Context) (entity.SeriesResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+	var request AddSeriesEntryRequest
+	var validate *validator.Validate = validator.New()
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.SeriesResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.SeriesResponse{}, http.StatusForbidden, err
+	}
+
+	// JSONリクエストデータを取得
+	if err := c.BindJSON(&request); err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// リクエストデータのバリデーションチェック
+	if err := validate.Struct(request); err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.SeriesResponse{}, http.StatusNotFound, err
+	}
+
+	// シリーズを登録したユーザのみ巻を登録できる
+	series, statusCode, err := findOwnSeries(db, c.Param("id"), user.ID)
+	if err != nil {
+		return entity.SeriesResponse{}, statusCode, err
+	}
+
+	// IDをキーに、書籍を取得
+	var book Book
+	if err := db.Where("id = ?", request.BookID).First(&book).Error; err != nil {
+		return entity.SeriesResponse{}, http.StatusNotFound, err
+	}
+	if book.WorkID == 0 {
+		return entity.SeriesResponse{}, http.StatusBadRequest, fmt.Errorf("book is not assigned to a work")
+	}
+
+	// シリーズIDと作品IDをキーに、巻を取得(無い場合は新規登録)
+	var entry SeriesEntry
+	err = db.Where(SeriesEntry{SeriesID: series.ID, WorkID: book.WorkID}).Assign(map[string]interface{}{"position": request.Position}).FirstOrCreate(&entry).Error
+	if err != nil {
+		return entity.SeriesResponse{}, http.StatusBadRequest, err
+	}
+
+	// レスポンス用データ生成
+	entries, err := getSeriesEntries(db, series.ID, user.ID)
+	if err != nil {
+		return entity.SeriesResponse{}, http.StatusInternalServerError, err
+	}
+	response := entity.SeriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		Entries:     entries,
+	}
+	return response, http.StatusOK, nil
+}
+
+// シリーズの巻削除サービス
+func (s Service) DeleteSeriesEntry(c *gin.Context) (StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// シリーズを登録したユーザのみ巻を削除できる
+	series, statusCode, err := findOwnSeries(db, c.Param("id"), user.ID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	// シリーズIDと巻IDをキーに、巻を取得
+	var entry SeriesEntry
+	if err := db.Where("id = ? AND series_id = ?", c.Param("entryId"), series.ID).First(&entry).Error; err != nil {
+		return http.StatusNotFound, err
+	}
+
+	if err := db.Delete(&entry).Error; err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// シリーズの次に読む巻の一覧取得サービス
+// ログインユーザがいずれかの巻をレビューしているシリーズについて、巻数順で最初の読了していない巻を返す
+func (s Service) GetNextInSeries(c *gin.Context) ([]entity.NextInSeries, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return []entity.NextInSeries{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return []entity.NextInSeries{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return []entity.NextInSeries{}, http.StatusNotFound, err
+	}
+
+	// ログインユーザがいずれかの巻をレビューしているシリーズを取得
+	var seriesList []Series
+	if err := db.Where("id IN (?)", db.Model(&SeriesEntry{}).Select("series_entries.series_id").Joins("join books on books.work_id = series_entries.work_id").Joins("join reviews on reviews.book_id = books.id").Where("reviews.user_id = ?", user.ID)).Order("name").Find(&seriesList).Error; err != nil {
+		// SELECT * FROM series
+		// WHERE id IN (
+		//   SELECT series_entries.series_id
+		//   FROM series_entries join books on books.work_id = series_entries.work_id
+		//   join reviews on reviews.book_id = books.id
+		//   WHERE reviews.user_id = user.ID
+		// )
+		// ORDER BY name
+		return []entity.NextInSeries{}, http.StatusInternalServerError, err
+	}
+
+	// シリーズごとに、巻数順で最初の読了していない巻を取得
+	response := []entity.NextInSeries{}
+	for _, series := range seriesList {
+		entries, err := getSeriesEntries(db, series.ID, user.ID)
+		if err != nil {
+			return []entity.NextInSeries{}, http.StatusInternalServerError, err
+		}
+		for _, entry := range entries {
+			if entry.ReadingStatus != entity.ReadingStatusFinish {
+				response = append(response, entity.NextInSeries{SeriesID: series.ID, SeriesName: series.Name, Entry: entry})
+				break
+			}
+		}
+	}
+
+	return response, http.StatusOK, nil
+}
+
+// シリーズを取得し、ログインユーザが登録したシリーズであるか検証する
+func findOwnSeries(tx *gorm.DB, id any, userID uint) (Series, StatusCode, error) {
+	var series Series
+	if err := tx.Where("id = ?", id).First(&series).Error; err != nil {
+		return Series{}, http.StatusNotFound, err
+	}
+
+	// 対象シリーズのユーザIDと、ログインユーザIDが一致していなければ操作させない
+	if series.UserID != userID {
+		return Series{}, http.StatusForbidden, fmt.Errorf("couldn't update this series")
+	}
+	return series, http.StatusOK, nil
+}
+
+// シリーズの巻を、巻数順に取得する(読書ステータスは、対象ユーザのいずれかの版に対するレビューから取得する)
+func getSeriesEntries(tx *gorm.DB, seriesID, userID uint) ([]entity.ResponseSeriesEntry, error) {
+	entries := []entity.ResponseSeriesEntry{}
+	if err := tx.Model(&SeriesEntry{}).Select(responseSeriesEntryColumns, userID).Joins("join works on works.id = series_entries.work_id").Where("series_entries.series_id = ?", seriesID).Order("series_entries.position, series_entries.id").Scan(&entries).Error; err != nil {
+		// SELECT [responseSeriesEntryColumns]
+		// FROM series_entries join works on works.id = series_entries.work_id
+		// WHERE series_entries.series_id = [シリーズID]
+		// ORDER BY series_entries.position, series_entries.id
+		return nil, err
+	}
+	return entries, nil
+}
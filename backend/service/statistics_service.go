@@ -0,0 +1,387 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+// 詳細な読書統計情報の期間の設定
+const (
+	defaultStatsMonths = 12  // 期間が指定されていない場合の月数(今月まで)
+	maxStatsMonths     = 120 // 指定できる期間の最大の月数
+)
+
+// 最も多く読んだ著者として返す人数
+const numOfMostReadAuthors = 5
+
+// ページ数の範囲(上限を含む、最後の範囲は上限なし)
+var pageCountBuckets = []struct {
+	Key string
+	Max uint
+}{
+	{"1-99", 99},
+	{"100-199", 199},
+	{"200-299", 299},
+	{"300-399", 399},
+	{"400-499", 499},
+	{"500+", math.MaxUint32},
+}
+
+// ページ数の不明な書籍の範囲
+const unknownPageCountKey = "unknown"
+
+// 詳細な読書統計情報の集計に用いる、読了したレビュー
+type statsReview struct {
+	ID           uint
+	Rating       float64
+	Tags         string
+	StartReadAt  time.Time
+	FinishReadAt time.Time
+	BookID       uint
+	Title        string
+	Author       string
+	NumOfPages   uint
+}
+
+// 詳細な読書統計情報の集計に用いる、書籍の著者、またはジャンル
+type statsBookLabel struct {
+	BookID uint
+	Kind   string // "author"、または"genre"
+	Slug   string // 著者ID(別名の場合は正式な著者のID)、またはジャンルのスラッグ
+	Name   string
+}
+
+// 詳細な読書統計情報取得サービス
+// ?from=[YYYY-MM]&to=[YYYY-MM]で対象期間を指定する(未指定の場合は今月までの12か月)
+// 期間内のレビューと、その書籍の著者、ジャンルを1回ずつ取得し、集計はアプリケーション側で行う
+func (s Service) GetDetailedReviewStats(c *gin.Context) (entity.DetailedStatsResponse, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.DetailedStatsResponse{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.DetailedStatsResponse{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.DetailedStatsResponse{}, http.StatusNotFound, err
+	}
+
+	// 対象期間を決定
+	from, to, err := statsMonthRange(c.Query("from"), c.Query("to"), userToday(user))
+	if err != nil {
+		return entity.DetailedStatsResponse{}, http.StatusBadRequest, err
+	}
+
+	// 期間内に読了したレビューと、その書籍の著者、ジャンルを取得
+	reviews, labels, err := loadStatsReviews(db, user.ID, from, to.AddDate(0, 1, 0))
+	if err != nil {
+		return entity.DetailedStatsResponse{}, http.StatusInternalServerError, err
+	}
+
+	return aggregateReviewStats(reviews, labels, from, to), http.StatusOK, nil
+}
+
+// 期間内(fromを含み、endを含まない)に読了したレビューと、その書籍の著者(別名は正式な著者にまとめる)、ジャンルを取得する
+func loadStatsReviews(db *gorm.DB, userID uint, from, end time.Time) ([]statsReview, []statsBookLabel, error) {
+	var reviews []statsReview
+	if err := db.Model(&Review{}).Select("reviews.id, reviews.rating, reviews.tags, reviews.start_read_at, reviews.finish_read_at, books.id as book_id, books.title, books.author, books.num_of_pages").Joins("join books on reviews.book_id = books.id").Where("reviews.user_id = ? AND reviews.reading_status = ? AND reviews.finish_read_at >= ? AND reviews.finish_read_at < ?", userID, entity.ReadingStatusFinish, from, end).Order("reviews.finish_read_at, reviews.id").Scan(&reviews).Error; err != nil {
+		// SELECT reviews.id, reviews.rating, reviews.tags, reviews.start_read_at, reviews.finish_read_at,
+		// 	books.id AS book_id, books.title, books.author, books.num_of_pages
+		// FROM reviews JOIN books ON reviews.book_id = books.id
+		// WHERE reviews.user_id = [userID] AND reviews.reading_status = 'Finish'
+		// 	AND reviews.finish_read_at >= [from] AND reviews.finish_read_at < [end]
+		// ORDER BY reviews.finish_read_at, reviews.id;
+		return nil, nil, err
+	}
+
+	var labels []statsBookLabel
+	if bookIDs := statsBookIDs(reviews); len(bookIDs) > 0 {
+		if err := db.Raw(`SELECT book_genres.book_id, 'genre' AS kind, genres.slug, genres.name
+			FROM book_genres JOIN genres ON genres.id = book_genres.genre_id
+			WHERE book_genres.book_id IN (?)
+			UNION ALL
+			SELECT DISTINCT book_authors.book_id, 'author' AS kind, CAST(canonical.id AS varchar) AS slug, canonical.name
+			FROM book_authors JOIN authors ON authors.id = book_authors.author_id
+			JOIN authors AS canonical ON canonical.id = coalesce(nullif(authors.canonical_id, 0), authors.id)
+			WHERE book_authors.role = ? AND book_authors.book_id IN (?)`, bookIDs, entity.AuthorRoleAuthor, bookIDs).Scan(&labels).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return reviews, labels, nil
+}
+
+// 読了したレビューを集計して、詳細な読書統計情報を返す
+func aggregateReviewStats(reviews []statsReview, labels []statsBookLabel, from, to time.Time) entity.DetailedStatsResponse {
+	response := entity.DetailedStatsResponse{
+		From:               from.Format("2006-01"),
+		To:                 to.Format("2006-01"),
+		Monthly:            []entity.MonthlyStats{},
+		RatingDistribution: []entity.RatingCount{},
+		PageCounts:         []entity.StatsBreakdown{},
+	}
+
+	// 月別の推移(読了していない月も含める)
+	monthIndex := map[string]int{}
+	monthRatings := map[string]*ratingSum{}
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		monthIndex[key] = len(response.Monthly)
+		monthRatings[key] = &ratingSum{}
+		response.Monthly = append(response.Monthly, entity.MonthlyStats{Month: key})
+	}
+
+	// 評価別の冊数(0.5刻み)
+	ratingCounts := map[float64]int64{}
+	for rating := 0.5; rating <= 5; rating += 0.5 {
+		ratingCounts[rating] = 0
+	}
+
+	// 書籍ごとの著者、ジャンル
+	authorsOfBook := map[uint][]statsBookLabel{}
+	genresOfBook := map[uint][]statsBookLabel{}
+	for _, label := range labels {
+		if label.Kind == "author" {
+			authorsOfBook[label.BookID] = append(authorsOfBook[label.BookID], label)
+		} else {
+			genresOfBook[label.BookID] = append(genresOfBook[label.BookID], label)
+		}
+	}
+
+	tags := newStatsCounter()
+	authors := newStatsCounter()
+	genres := newStatsCounter()
+	pageCounts := newStatsCounter()
+	total := ratingSum{}
+	var daysToFinish, numOfDaysToFinish int
+	for _, review := range reviews {
+		pages := int64(review.NumOfPages)
+		response.NumOfReadBooks++
+		response.NumOfReadPages += pages
+
+		month := review.FinishReadAt.Format("2006-01")
+		if i, ok := monthIndex[month]; ok {
+			response.Monthly[i].NumOfReadBooks++
+			response.Monthly[i].NumOfReadPages += pages
+		}
+		if review.Rating > 0 {
+			total.add(review.Rating)
+			if sum, ok := monthRatings[month]; ok {
+				sum.add(review.Rating)
+			}
+			ratingCounts[math.Round(review.Rating*2)/2]++
+		}
+
+		for _, tag := range splitTags(review.Tags) {
+			tags.add(tag, tag, pages)
+		}
+		if bookAuthors, ok := authorsOfBook[review.BookID]; ok {
+			for _, author := range bookAuthors {
+				authors.add(author.Slug, author.Name, pages)
+			}
+		} else if review.Author != "" {
+			// 著者が登録されていない書籍は、著者文字列で集計する
+			authors.add(review.Author, review.Author, pages)
+		}
+		for _, genre := range genresOfBook[review.BookID] {
+			genres.add(genre.Slug, genre.Name, pages)
+		}
+		pageCountKey := pageCountBucket(review.NumOfPages)
+		pageCounts.add(pageCountKey, pageCountKey, pages)
+
+		if !review.StartReadAt.IsZero() && !review.FinishReadAt.Before(review.StartReadAt) {
+			daysToFinish += daysBetween(review.StartReadAt, review.FinishReadAt) + 1
+			numOfDaysToFinish++
+		}
+
+		if review.NumOfPages > 0 {
+			if response.LongestBook == nil || review.NumOfPages > response.LongestBook.NumOfPages {
+				response.LongestBook = toStatsBook(review)
+			}
+			if response.ShortestBook == nil || review.NumOfPages < response.ShortestBook.NumOfPages {
+				response.ShortestBook = toStatsBook(review)
+			}
+		}
+	}
+
+	response.AverageRating = total.average()
+	for i := range response.Monthly {
+		response.Monthly[i].AverageRating = monthRatings[response.Monthly[i].Month].average()
+	}
+	for rating := 0.5; rating <= 5; rating += 0.5 {
+		response.RatingDistribution = append(response.RatingDistribution, entity.RatingCount{Rating: rating, Count: ratingCounts[rating]})
+	}
+	if numOfDaysToFinish > 0 {
+		response.AverageDaysToFinish = roundProgress(float64(daysToFinish) / float64(numOfDaysToFinish))
+	}
+
+	response.Tags = tags.sorted()
+	response.Authors = authors.sorted()
+	response.Genres = genres.sorted()
+	response.MostReadAuthors = response.Authors
+	if len(response.MostReadAuthors) > numOfMostReadAuthors {
+		response.MostReadAuthors = response.MostReadAuthors[:numOfMostReadAuthors]
+	}
+	// ページ数の範囲は、該当する書籍が無い範囲も含めて範囲の順に返す
+	for _, bucket := range pageCountBuckets {
+		response.PageCounts = append(response.PageCounts, pageCounts.get(bucket.Key))
+	}
+	response.PageCounts = append(response.PageCounts, pageCounts.get(unknownPageCountKey))
+
+	return response
+}
+
+// 評価の合計と件数
+type ratingSum struct {
+	Sum   float64
+	Count int64
+}
+
+func (r *ratingSum) add(rating float64) {
+	r.Sum += rating
+	r.Count++
+}
+
+// 評価の平均を返す(評価が無い場合は0)
+func (r *ratingSum) average() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+	return roundProgress(r.Sum / float64(r.Count))
+}
+
+// 項目別の冊数、ページ数の集計
+type statsCounter struct {
+	items map[string]*entity.StatsBreakdown
+}
+
+func newStatsCounter() statsCounter {
+	return statsCounter{items: map[string]*entity.StatsBreakdown{}}
+}
+
+func (c statsCounter) add(key, name string, pages int64) {
+	item, ok := c.items[key]
+	if !ok {
+		item = &entity.StatsBreakdown{Key: key, Name: name}
+		c.items[key] = item
+	}
+	item.NumOfReadBooks++
+	item.NumOfReadPages += pages
+}
+
+// 項目の集計結果を返す(該当しない場合は0件とする)
+func (c statsCounter) get(key string) entity.StatsBreakdown {
+	if item, ok := c.items[key]; ok {
+		return *item
+	}
+	return entity.StatsBreakdown{Key: key, Name: key}
+}
+
+// 集計結果を、冊数、ページ数の多い順に返す
+func (c statsCounter) sorted() []entity.StatsBreakdown {
+	result := []entity.StatsBreakdown{}
+	for _, item := range c.items {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].NumOfReadBooks != result[j].NumOfReadBooks {
+			return result[i].NumOfReadBooks > result[j].NumOfReadBooks
+		}
+		if result[i].NumOfReadPages != result[j].NumOfReadPages {
+			return result[i].NumOfReadPages > result[j].NumOfReadPages
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ページ数の範囲を返す
+func pageCountBucket(numOfPages uint) string {
+	if numOfPages == 0 {
+		return unknownPageCountKey
+	}
+	for _, bucket := range pageCountBuckets {
+		if numOfPages <= bucket.Max {
+			return bucket.Key
+		}
+	}
+	return pageCountBuckets[len(pageCountBuckets)-1].Key
+}
+
+// レビューを統計情報の書籍構造体に変換する
+func toStatsBook(review statsReview) *entity.StatsBook {
+	return &entity.StatsBook{
+		ReviewID:     review.ID,
+		BookID:       review.BookID,
+		Title:        review.Title,
+		Author:       review.Author,
+		NumOfPages:   review.NumOfPages,
+		Rating:       review.Rating,
+		FinishReadAt: formatDate(review.FinishReadAt),
+	}
+}
+
+// レビューの書籍のIDを、重複を除いて返す
+func statsBookIDs(reviews []statsReview) []uint {
+	seen := map[uint]bool{}
+	var bookIDs []uint
+	for _, review := range reviews {
+		if !seen[review.BookID] {
+			seen[review.BookID] = true
+			bookIDs = append(bookIDs, review.BookID)
+		}
+	}
+	return bookIDs
+}
+
+// 統計情報の対象期間の最初の月と最後の月(いずれも1日)を返す
+func statsMonthRange(fromParam, toParam string, today time.Time) (time.Time, time.Time, error) {
+	to := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if toParam != "" {
+		parsed, err := time.Parse("2006-01", toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, -(defaultStatsMonths - 1), 0)
+	if fromParam != "" {
+		parsed, err := time.Parse("2006-01", fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	if months > maxStatsMonths {
+		return time.Time{}, time.Time{}, fmt.Errorf("period must be at most %d months", maxStatsMonths)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Work entity.Work
+
+// 作品詳細取得サービス(作品の全ての版を返す)
+func (s Service) GetWork(c *gin.Context) (entity.WorkResponse, StatusCode, error) {
+	db := db.GetDB()
+	var work Work
+
+	// IDをキーに、作品を取得
+	if err := db.Where("id = ?", c.Param("id")).First(&work).Error; err != nil {
+		return entity.WorkResponse{}, http.StatusNotFound, err
+	}
+
+	response := entity.WorkResponse{
+		ID:       work.ID,
+		Title:    work.Title,
+		Author:   work.Author,
+		Editions: []entity.WorkEdition{},
+	}
+
+	// 作品IDをキーに、版(書籍)と公開レビューの件数を取得
+	if err := db.Model(&Book{}).Select("books.id, books.title, books.author, books.thumbnail_link, books.published_date, books.num_of_pages, books.isbn10, books.isbn13, count(reviews.id) as num_of_reviews").Joins("left join reviews on reviews.book_id = books.id AND reviews.visibility = ?", entity.ReviewVisibilityPublic).Where("books.work_id = ?", work.ID).Group("books.id").Order("books.published_date, books.id").Scan(&response.Editions).Error; err != nil {
+		// SELECT books.id, books.title, books.author, books.thumbnail_link, books.published_date,
+		//   books.num_of_pages, books.isbn10, books.isbn13, count(reviews.id) as num_of_reviews
+		// FROM books left join reviews on reviews.book_id = books.id AND reviews.visibility = 'public'
+		// WHERE books.work_id = [作品ID]
+		// GROUP BY books.id
+		// ORDER BY books.published_date, books.id
+		return entity.WorkResponse{}, http.StatusInternalServerError, err
+	}
+
+	// 全ての版の公開レビューを集計
+	community, err := getWorkCommunityStats(db, work.ID)
+	if err != nil {
+		return entity.WorkResponse{}, http.StatusInternalServerError, err
+	}
+	response.Community = community
+
+	return response, http.StatusOK, nil
+}
+
+// 作品が登録されていない書籍について、タイトルと著者から作品を登録する
+// (作品をエンティティとして管理する以前に登録された書籍の移行用)
+func MigrateBookWorks() error {
+	db := db.GetDB()
+	var books []Book
+	if err := db.Where("work_id IS NULL OR work_id = 0").Find(&books).Error; err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return assignWork(tx, &book, 0)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(books) > 0 {
+		log.Printf("migrated works of %d books", len(books))
+	}
+	return nil
+}
+
+// 書籍を作品に関連付ける
+// 作品IDが指定されていない場合は、タイトルと著者が一致する作品を探し、無い場合は新規登録する
+func assignWork(tx *gorm.DB, book *Book, workID uint) error {
+	var work Work
+	if workID != 0 {
+		if err := tx.Where("id = ?", workID).First(&work).Error; err != nil {
+			return err
+		}
+	} else {
+		matchKey := workMatchKey(book.Title, book.Author)
+		err := tx.Where("match_key = ?", matchKey).Order("id").First(&work).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			work = Work{Title: book.Title, Author: book.Author, MatchKey: matchKey}
+			err = tx.Create(&work).Error
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	book.WorkID = work.ID
+	return tx.Model(book).Update("work_id", work.ID).Error
+}
+
+// タイトルと著者から、版の照合用キーを生成する
+func workMatchKey(title, author string) string {
+	return compactLower(title) + "/" + compactLower(author)
+}
+
+// ユーザの、書籍と同じ作品(いずれかの版)に対するレビューを取得する
+func findWorkReview(tx *gorm.DB, userID uint, book Book) (Review, error) {
+	var review Review
+	err := tx.Where("user_id = ? AND (book_id = ? OR book_id IN (SELECT id FROM books WHERE work_id = ? AND work_id <> 0))", userID, book.ID, book.WorkID).Order("id").First(&review).Error
+	return review, err
+}
+
+// 作品の全ての版に対する、全ユーザの公開レビューを集計する
+func getWorkCommunityStats(db *gorm.DB, workID uint) (entity.BookCommunityStats, error) {
+	return getCommunityStats(func() *gorm.DB {
+		return db.Model(&Review{}).Where("reviews.book_id IN (SELECT id FROM books WHERE work_id = ?) AND reviews.visibility = ?", workID, entity.ReviewVisibilityPublic)
+	})
+}
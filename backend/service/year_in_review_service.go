@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KoyoMiyazaki/Book-Reviewer/db"
+	"github.com/KoyoMiyazaki/Book-Reviewer/entity"
+	"github.com/KoyoMiyazaki/Book-Reviewer/report"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// 年間の読書のまとめの出力形式
+const (
+	yearInReviewFormatJSON = "json"
+	yearInReviewFormatHTML = "html"
+	yearInReviewFormatSVG  = "svg"
+	yearInReviewFormatPNG  = "png"
+)
+
+// 年間の読書のまとめに含める、評価の高い書籍、よく付けたタグ、よく読んだ著者の件数
+const numOfYearInReviewItems = 5
+
+// 年間の読書のまとめ取得サービス
+// ?format=[json|html|svg|png]で出力形式を指定する(未指定の場合はjson)
+func (s Service) GetYearInReview(c *gin.Context) (entity.YearInReviewReport, StatusCode, error) {
+	db := db.GetDB()
+	var user User
+
+	// JWTトークン検証
+	authHeader := c.Request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, statusCode, err := s.VerifyToken(tokenString)
+	if err != nil {
+		return entity.YearInReviewReport{}, statusCode, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return entity.YearInReviewReport{}, http.StatusForbidden, err
+	}
+
+	// メールアドレスをキーに、ユーザを取得
+	if err := db.Where("email = ?", claims["email"]).First(&user).Error; err != nil {
+		return entity.YearInReviewReport{}, http.StatusNotFound, err
+	}
+
+	// パラメータの検証
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil || year < 1 || year > 9999 {
+		return entity.YearInReviewReport{}, http.StatusBadRequest, fmt.Errorf("year must be between 1 and 9999")
+	}
+	format := c.DefaultQuery("format", yearInReviewFormatJSON)
+	switch format {
+	case yearInReviewFormatJSON, yearInReviewFormatHTML, yearInReviewFormatSVG, yearInReviewFormatPNG:
+	default:
+		return entity.YearInReviewReport{}, http.StatusBadRequest, fmt.Errorf("format must be one of json, html, svg or png")
+	}
+
+	// 対象年に読了したレビューを取得して集計
+	from, end := yearRange(year)
+	reviews, labels, err := loadStatsReviews(db, user.ID, from, end)
+	if err != nil {
+		return entity.YearInReviewReport{}, http.StatusInternalServerError, err
+	}
+	summary := buildYearInReview(year, user.Name, reviews, labels)
+
+	response := entity.YearInReviewReport{Summary: summary}
+	switch format {
+	case yearInReviewFormatHTML:
+		response.ContentType = "text/html; charset=utf-8"
+		response.Data, err = report.HTML(summary)
+	case yearInReviewFormatSVG:
+		response.ContentType = "image/svg+xml"
+		response.Data = report.SVG(summary)
+	case yearInReviewFormatPNG:
+		response.ContentType = "image/png"
+		response.Data, err = report.PNG(summary)
+	}
+	if err != nil {
+		return entity.YearInReviewReport{}, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+// 対象年に読了したレビュー(読了日順)から、年間の読書のまとめを作成する
+func buildYearInReview(year int, userName string, reviews []statsReview, labels []statsBookLabel) entity.YearInReview {
+	from, end := yearRange(year)
+	stats := aggregateReviewStats(reviews, labels, from, end.AddDate(0, -1, 0))
+
+	summary := entity.YearInReview{
+		Year:            year,
+		UserName:        userName,
+		NumOfReadBooks:  stats.NumOfReadBooks,
+		NumOfReadPages:  stats.NumOfReadPages,
+		AverageRating:   stats.AverageRating,
+		TopRatedBooks:   []entity.StatsBook{},
+		LongestBook:     stats.LongestBook,
+		FavoriteTags:    stats.Tags,
+		FavoriteAuthors: stats.Authors,
+		Monthly:         stats.Monthly,
+	}
+	if len(summary.FavoriteTags) > numOfYearInReviewItems {
+		summary.FavoriteTags = summary.FavoriteTags[:numOfYearInReviewItems]
+	}
+	if len(summary.FavoriteAuthors) > numOfYearInReviewItems {
+		summary.FavoriteAuthors = summary.FavoriteAuthors[:numOfYearInReviewItems]
+	}
+	if len(reviews) > 0 {
+		summary.FirstBook = toStatsBook(reviews[0])
+		summary.LastBook = toStatsBook(reviews[len(reviews)-1])
+	}
+
+	// 評価の高い順(同じ評価の場合は先に読了した順)に並べる
+	rated := []statsReview{}
+	for _, review := range reviews {
+		if review.Rating > 0 {
+			rated = append(rated, review)
+		}
+	}
+	sort.SliceStable(rated, func(i, j int) bool { return rated[i].Rating > rated[j].Rating })
+	for i := 0; i < len(rated) && i < numOfYearInReviewItems; i++ {
+		summary.TopRatedBooks = append(summary.TopRatedBooks, *toStatsBook(rated[i]))
+	}
+	return summary
+}
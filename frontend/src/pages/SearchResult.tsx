@@ -148,7 +148,7 @@ const SearchResult = () => {
         });
       }
       const data = await res.data;
-      setBooks(data.data);
+      setBooks(data.data.items);
     } catch (error) {
       if (error instanceof AxiosError) {
         if (error.response?.status === StatusCodes.UNAUTHORIZED) {